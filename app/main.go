@@ -1,11 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
+	"unicode/utf16"
 
 	"github.com/pawel-ochrymowicz/google-maps-to-waze/pkg/maps"
 	"github.com/pawel-ochrymowicz/google-maps-to-waze/pkg/telegram"
@@ -22,11 +28,20 @@ type telegramOpts struct {
 type opts struct {
 	telegram           telegramOpts
 	disableHealthCheck bool
+	consentCookie      string
+	cacheFile          string
 }
 
 const (
 	healthCheckPath = "/health"
 	serverPort      = 8080
+	// shutdownTimeout is the time given to in-flight webhook requests to finish on shutdown.
+	shutdownTimeout = 10 * time.Second
+	// cacheSize is the number of resolved short links kept in memory or in the cache file.
+	cacheSize = 1024
+	cacheTTL  = 24 * time.Hour
+	// placeListLimit is the number of places of a My Maps map or a saved list replied with.
+	placeListLimit = 20
 )
 
 func envOpts() *opts {
@@ -46,11 +61,25 @@ func envOpts() *opts {
 			WebhookLink: webhookLink,
 		},
 		disableHealthCheck: os.Getenv("DISABLE_HEALTH_CHECK") == "true",
+		consentCookie:      os.Getenv("GOOGLE_CONSENT_COOKIE"),
+		cacheFile:          os.Getenv("CACHE_FILE"),
 	}
 }
 
 func main() {
+	// Cancel polling and in-flight resolutions on shutdown.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	opts := envOpts()
+	cache, err := newCache(opts.cacheFile)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to initialize cache"))
+	}
+	redirects := maps.NewRedirectResolver(httpClient, maps.WithHttpOpts(maps.WithConsentCookie(opts.consentCookie)))
+	resolver = maps.NewCachingResolver(redirects.Resolve, cache, maps.WithCacheErrorHandler(func(key string, err error) {
+		log.Warnf("resolved link %s left uncached: %v", key, err)
+	}))
 	tg, err := telegram.New(opts.telegram.Token)
 	if err != nil {
 		panic(errors.Wrap(err, "failed to initialize telegram"))
@@ -65,7 +94,7 @@ func main() {
 				ch <- err
 			}
 
-			if err := tg.Poll(onMessage); err != nil {
+			if err := tg.Poll(ctx, onMessage); err != nil && ctx.Err() == nil {
 				ch <- err
 			}
 		}()
@@ -84,36 +113,265 @@ func main() {
 		serverOpts = append(serverOpts, withHealthCheck())
 	}
 
+	var srv *http.Server
 	if len(serverOpts) > 0 {
+		srv = server(serverOpts...)
+		// Webhook requests get cancelled on shutdown as well.
+		srv.BaseContext = func(net.Listener) context.Context { return ctx }
 		go func() {
 			log.Infof("Starting server on port %d", serverPort)
-			srv := server(serverOpts...)
-			if err := srv.ListenAndServe(); err != nil {
+			if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 				ch <- err
 			}
 		}()
 	}
-	panic(<-ch)
+
+	select {
+	case err := <-ch:
+		panic(err)
+	case <-ctx.Done():
+		stats := resolver.Stats()
+		log.Infof("Shutting down, cache hits: %d, misses: %d, shared: %d", stats.Hits, stats.Misses, stats.Shared)
+	}
+	if srv != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Errorf("failed to shut down server: %v", err)
+		}
+	}
 }
 
 const (
 	// welcomeMessage is a message that is sent when a user starts the bot.
 	welcomeMessage = `
 Welcome to Google Maps to Waze bot!
-Send me a Google Maps link and I will send you a Waze link.
+Send me a Google Maps, Street View, Google Earth, Apple Maps, OpenStreetMap, Organic Maps, Amap, Baidu, Yandex Maps, 2GIS, HERE WeGo, Bing Maps or Mapy.cz link, or a geo URI, and I will send you a Waze link.
+Send me a Waze link and I will send you a Google Maps link.
 
 Examples:
 - Shortened: https://goo.gl/maps/1JZ8Zq4J1Z8Zq4
 - Full: https://www.google.com/maps/dir/?api=1&destination=51.107885,17.038538
+- Street View: https://www.google.com/maps/@51.1068963,17.0777458,3a,75y,90h,90t
+- Google Earth: https://earth.google.com/web/@48.85837,2.29448,35a,1250d,35y,0h,60t,0r
+- My Maps or a saved list: https://www.google.com/maps/d/viewer?mid=1a2B3c4D5e6F7g8H9i0J
+- Apple Maps: https://maps.apple.com/?ll=51.106940,17.077210&q=Hala%20Stulecia
+- OpenStreetMap: https://osm.org/go/0OBMdbXq
+- Organic Maps: https://omaps.app/w4NCMunrVR/Hala_Stulecia
+- Amap: https://uri.amap.com/marker?position=116.397477,39.908692
+- Baidu: https://api.map.baidu.com/marker?location=39.915,116.404&output=html
+- Yandex Maps: https://yandex.ru/maps/?pt=37.617635,55.755814&z=17
+- 2GIS: https://2gis.ru/moscow/geo/4504235282610451/37.617635,55.755814
+- HERE WeGo: https://share.here.com/l/50.08804,14.42076,Old%20Town%20Square
+- Bing Maps: https://www.bing.com/maps?cp=50.08804~14.42076&lvl=16
+- Mapy.cz: https://mapy.cz/zakladni?x=14.42076&y=50.08804&z=16
+- Waze: https://waze.com/ul?ll=51.1069402,17.0772095&navigate=yes
+- Geo URI: geo:51.1069402,17.0772095;u=35 or geo:0,0?q=51.1069402,17.0772095(Hala+Stulecia)
+- Coordinates: 51°06'28.4"N 17°02'18.7"E or 51.1079, 17.0385
+- Plus Code: 9F3V434G+QV or 434G+QV Wrocław
+- MGRS: 33U XS 45414 63769
+- British National Grid: TQ 30080 80001
+- Swiss LV95: LV95 2 600 000 / 1 200 000
+- Geohash: geohash u3h4sxep or http://geohash.org/u3h4sxep
+- Maidenhead locator: JO81ld
 - Any text with a link: foo bar https://www.google.com/maps/dir/?api=1&destination=51.107885,17.038538
 `
+
+	// unsupportedLinkMessage is a message that is sent when a link points outside of the supported map services.
+	unsupportedLinkMessage = "This link is not supported, send me a Google Maps, Street View, Google Earth, Apple Maps, OpenStreetMap, Organic Maps, Amap, Baidu, Yandex Maps, 2GIS, HERE WeGo, Bing Maps or Mapy.cz link, or a geo URI."
+
+	// linksMessage is a message with the links to the location, the Waze one first so that it gets previewed.
+	linksMessage = "%s\nOrganic Maps: %s\nPlus Code: %s\nMGRS: %s\nGeo URI: %s"
+
+	// amapLinkMessage is appended to the links of locations in China, where Amap is the app of choice.
+	amapLinkMessage = "\nAmap: %s"
+
+	// cameraLinkMessage is appended to the links of Street View and Google Earth links, which point at where the camera stands.
+	cameraLinkMessage = "\n%s location, heading %.0f°: %s"
+
+	// placeListMessage is a message that is sent along with the Waze links of the places of a My Maps map or a saved list.
+	placeListMessage = "%s has %d places:"
+
+	// placeListSingleMessage stands for placeListMessage for lists of a single place.
+	placeListSingleMessage = "%s has 1 place:"
+
+	// placeListDefaultTitle stands for the title of lists that have none.
+	placeListDefaultTitle = "This list"
+
+	// placeListMoreMessage ends the reply to lists with more places than placeListLimit.
+	placeListMoreMessage = "…and %d more"
+
+	// ambiguousLinkMessage is a message that is sent along with the Waze links when a link points at several places.
+	ambiguousLinkMessage = "This link points at several places, pick the one you meant:"
 )
 
-// httpClient is a http client used to make requests to Google Maps
-var httpClient = &http.Client{Timeout: 15 * time.Second, Jar: nil}
+// httpClient is a http client used to make requests to Google Maps.
+// Its transport refuses hosts other than the known map services and private addresses, and it follows the short links
+// of the other services up to as many redirects as the resolver does.
+var httpClient = newHttpClient()
+
+func newHttpClient() *http.Client {
+	client := maps.NewSafeClient(maps.DefaultMaxRedirects)
+	client.Timeout = 15 * time.Second
+	return client
+}
+
+// resolver resolves Google Maps links, following short links hop by hop and caching where they lead.
+// It is built in main, once the cache is configured.
+var resolver *maps.CachingResolver
+
+// registry picks the parser of a link by its host, handing the links of other hosts to the resolver.
+var registry = newRegistry()
+
+func newRegistry() *maps.Registry {
+	registry := maps.NewRegistry(func(ctx context.Context, u *url.URL) (maps.Location, error) {
+		// My Maps maps and saved lists hold several places, so they are parsed as lists rather than resolved.
+		if maps.IsPlaceListURL(u) {
+			return parsePlaceList(ctx, u)
+		}
+		link, err := resolver.Resolve(ctx, u)
+		var listErr *maps.PlaceListError
+		if errors.As(err, &listErr) {
+			return parsePlaceList(ctx, listErr.URL)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return link, nil
+	})
+	registry.Register(func(ctx context.Context, u *url.URL) (maps.Location, error) {
+		link, err := maps.ParseAppleMapsFromURL(u)
+		if err != nil {
+			return nil, err
+		}
+		// Addresses shared without coordinates are looked up on Google Maps.
+		if search, ok := link.SearchURL(); ok {
+			found, err := resolver.Resolve(ctx, search)
+			if err != nil {
+				return nil, err
+			}
+			return found, nil
+		}
+		return link, nil
+	}, maps.AppleMapsHosts...)
+	registry.Register(func(_ context.Context, u *url.URL) (maps.Location, error) {
+		link, err := maps.ParseOSMFromURL(u)
+		if err != nil {
+			return nil, err
+		}
+		return link, nil
+	}, maps.OSMHosts...)
+	organicMaps := func(_ context.Context, u *url.URL) (maps.Location, error) {
+		link, err := maps.ParseOrganicMapsFromURL(u)
+		if err != nil {
+			return nil, err
+		}
+		return link, nil
+	}
+	registry.Register(organicMaps, maps.OrganicMapsHosts...)
+	registry.Register(func(ctx context.Context, u *url.URL) (maps.Location, error) {
+		link, err := maps.ParseWazeFromURL(ctx, u, maps.HttpGetToInput(httpClient))
+		if err != nil {
+			return nil, err
+		}
+		return link, nil
+	}, maps.WazeHosts...)
+	registry.Register(func(_ context.Context, u *url.URL) (maps.Location, error) {
+		geohash, err := maps.ParseGeohashFromURL(u)
+		if err != nil {
+			return nil, err
+		}
+		return geohash, nil
+	}, maps.GeohashHosts...)
+	registry.Register(func(ctx context.Context, u *url.URL) (maps.Location, error) {
+		link, err := maps.ParseAmapFromURL(ctx, u, maps.HttpGetToTarget(httpClient))
+		if err != nil {
+			return nil, err
+		}
+		return link, nil
+	}, maps.AmapHosts...)
+	registry.Register(func(_ context.Context, u *url.URL) (maps.Location, error) {
+		link, err := maps.ParseBaiduFromURL(u)
+		if err != nil {
+			return nil, err
+		}
+		return link, nil
+	}, maps.BaiduHosts...)
+	yandex := func(ctx context.Context, u *url.URL) (maps.Location, error) {
+		link, err := maps.ParseYandexFromURL(ctx, u, maps.HttpGetToTarget(httpClient))
+		if err != nil {
+			return nil, err
+		}
+		return link, nil
+	}
+	registry.Register(yandex, maps.YandexHosts...)
+	registry.Register(func(ctx context.Context, u *url.URL) (maps.Location, error) {
+		link, err := maps.ParseTwoGISFromURL(ctx, u, maps.HttpGetToTarget(httpClient))
+		if err != nil {
+			return nil, err
+		}
+		return link, nil
+	}, maps.TwoGISHosts...)
+	registry.Register(func(ctx context.Context, u *url.URL) (maps.Location, error) {
+		link, err := maps.ParseHereFromURL(ctx, u, maps.HttpGetToTarget(httpClient))
+		if err != nil {
+			return nil, err
+		}
+		return link, nil
+	}, maps.HereHosts...)
+	registry.Register(func(ctx context.Context, u *url.URL) (maps.Location, error) {
+		link, err := maps.ParseBingFromURL(ctx, u, maps.HttpGetToTarget(httpClient))
+		if err != nil {
+			return nil, err
+		}
+		return link, nil
+	}, maps.BingHosts...)
+	registry.Register(func(ctx context.Context, u *url.URL) (maps.Location, error) {
+		link, err := maps.ParseMapyCzFromURL(ctx, u, maps.HttpGetToTarget(httpClient))
+		if err != nil {
+			return nil, err
+		}
+		return link, nil
+	}, maps.MapyCzHosts...)
+	registry.RegisterScheme(organicMaps, maps.Ge0Scheme)
+	registry.RegisterScheme(yandex, maps.YandexSchemes...)
+	registry.RegisterScheme(func(ctx context.Context, u *url.URL) (maps.Location, error) {
+		geoURI, err := maps.ParseGeoURIFromURL(u)
+		if err != nil {
+			return nil, err
+		}
+		// Addresses searched for by Android apps are looked up on Google Maps.
+		if search, ok := geoURI.SearchURL(); ok {
+			link, err := resolver.Resolve(ctx, search)
+			if err != nil {
+				return nil, err
+			}
+			return link, nil
+		}
+		return geoURI, nil
+	}, maps.GeoScheme)
+	return registry
+}
+
+// parsePlaceList fetches the places of a My Maps map or a saved list.
+func parsePlaceList(ctx context.Context, u *url.URL) (maps.Location, error) {
+	list, err := maps.ParsePlaceListFromURL(ctx, u, maps.HttpGetToInput(httpClient))
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// newCache creates an on-disk cache when a file is given, an in-memory one otherwise.
+func newCache(file string) (maps.Cache, error) {
+	if file == "" {
+		return maps.NewLRUCache(cacheSize, cacheTTL), nil
+	}
+	return maps.NewFileCache(file, cacheSize, cacheTTL)
+}
 
 // onMessage is a callback function that is called when a message is received.
-func onMessage(message *telegram.Message) error {
+func onMessage(ctx context.Context, message *telegram.Message) error {
 	if message.Text == "/start" {
 		return message.Reply(&telegram.Reply{
 			Text:   welcomeMessage,
@@ -125,21 +383,219 @@ func onMessage(message *telegram.Message) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to parse url from message")
 	}
-	var googleMapsLink *maps.GoogleMapsLink
-	googleMapsLink, err = maps.ParseGoogleMapsFromURL(u, maps.HttpGetToInput(httpClient))
+	// Messages without a link may still carry a plus code or plain coordinates.
+	if u.String() == "" {
+		location, err := textLocation(ctx, message.Text)
+		if err != nil {
+			return err
+		}
+		return replyLinks(message, location)
+	}
+	var location maps.Location
+	location, err = registry.Parse(ctx, u)
+	// Supported links failing to fetch, e.g. over the body limit or the redirect bound, get the generic failure reply.
+	if unsupportedLink(err) {
+		log.Warnf("rejected link %s: %v", u, err)
+		return message.Reply(&telegram.Reply{
+			Text: unsupportedLinkMessage,
+		})
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse map link: %s", u)
+	}
+	if googleMapsLink, ok := location.(*maps.GoogleMapsLink); ok && googleMapsLink.Ambiguous() {
+		return replyCandidates(message, googleMapsLink.Candidates())
+	}
+	if list, ok := location.(*maps.PlaceList); ok {
+		return replyPlaces(message, list)
+	}
+	// Waze links convert the other way round.
+	if _, ok := location.(*maps.WazeLink); ok {
+		var googleMapsLink *maps.GoogleMapsLink
+		googleMapsLink, err = maps.GoogleMapsFromLocation(location)
+		if err != nil {
+			return errors.Wrap(err, "failed to map location to google maps link")
+		}
+		return message.Reply(&telegram.Reply{
+			Text: googleMapsLink.URL().String(),
+		})
+	}
+	return replyLinks(message, location)
+}
+
+// unsupportedLink tells whether the link was rejected for its host, its scheme or its private address.
+func unsupportedLink(err error) bool {
+	return errors.Is(err, maps.ErrHostNotAllowed) || errors.Is(err, maps.ErrSchemeNotAllowed) || errors.Is(err, maps.ErrPrivateAddress)
+}
+
+// textLocation finds the location given first in the text of a message, be it a plus code, a grid reference or coordinates.
+// Short plus codes are recovered from the location of the locality following them, looked up on Google Maps.
+func textLocation(ctx context.Context, messageText string) (maps.Location, error) {
+	location, err := text.FindLocation(messageText)
+	var shortErr *text.ShortPlusCodeError
+	if errors.As(err, &shortErr) {
+		return recoverPlusCode(ctx, shortErr.Code, shortErr.Locality)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find location in message")
+	}
+	return location, nil
+}
+
+// recoverPlusCode recovers the full plus code of the short one from the location of the locality, looked up on Google Maps.
+func recoverPlusCode(ctx context.Context, code maps.PlusCode, locality string) (maps.Location, error) {
+	if locality == "" {
+		return nil, errors.Errorf("missing locality of short plus code: %s", code)
+	}
+	reference, err := resolver.Resolve(ctx, maps.GoogleMapsSearchURL(locality))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to locate locality of plus code: %s", locality)
+	}
+	latLng, err := reference.LatLng()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to extract lat lng of locality")
+	}
+	full, err := maps.RecoverPlusCode(code, latLng)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to recover plus code: %s", code)
+	}
+	return full, nil
+}
+
+// replyLinks replies with the links to the location in the other apps, its plus code, its MGRS reference and its geo URI.
+// Locations in China get an Amap link as well, shifted into the GCJ-02 coordinates Amap expects.
+// Street View and Google Earth locations are labelled as such, along with the heading of the camera.
+func replyLinks(message *telegram.Message, location maps.Location) error {
+	wazeLink, err := maps.WazeFromLocation(location)
+	if err != nil {
+		return errors.Wrap(err, "failed to map location to waze link")
+	}
+	organicMapsLink, err := maps.OrganicMapsFromLocation(location)
+	if err != nil {
+		return errors.Wrap(err, "failed to map location to organic maps link")
+	}
+	latLng, err := location.LatLng()
+	if err != nil {
+		return errors.Wrap(err, "failed to extract lat lng from location")
+	}
+	plusCode, err := maps.EncodePlusCode(latLng, maps.DefaultPlusCodeLength)
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse google maps link: %s", u)
+		return errors.Wrap(err, "failed to encode plus code")
 	}
-	var wazeLink *maps.WazeLink
-	wazeLink, err = maps.WazeFromLocation(googleMapsLink)
+	mgrs, err := maps.EncodeMGRS(latLng, maps.DefaultMGRSPrecision)
 	if err != nil {
-		return errors.Wrap(err, "failed to map google maps url to waze link")
+		return errors.Wrap(err, "failed to encode mgrs")
+	}
+	geoURI, err := maps.GeoURIFromLocation(location)
+	if err != nil {
+		return errors.Wrap(err, "failed to map location to geo uri")
+	}
+	reply := fmt.Sprintf(linksMessage, wazeLink.URL(), organicMapsLink.URL(), plusCode, mgrs, geoURI)
+	if maps.InChina(latLng) {
+		amapLink, err := maps.AmapFromLocation(location)
+		if err != nil {
+			return errors.Wrap(err, "failed to map location to amap link")
+		}
+		reply += fmt.Sprintf(amapLinkMessage, amapLink.URL())
+	}
+	if viewed, ok := location.(interface{ Camera() *maps.Camera }); ok && viewed.Camera() != nil {
+		camera := viewed.Camera()
+		reply += fmt.Sprintf(cameraLinkMessage, camera.Kind, camera.Heading, camera.URL())
+	}
+	return message.Reply(&telegram.Reply{
+		Text: reply,
+	})
+}
+
+// replyCandidates replies with a Waze link for every candidate location, letting the user pick the right one.
+func replyCandidates(message *telegram.Message, candidates []maps.Candidate) error {
+	lines := []string{ambiguousLinkMessage}
+	for _, candidate := range candidates {
+		wazeLink, err := maps.WazeFromLatLng(candidate.LatLng)
+		if err != nil {
+			return errors.Wrap(err, "failed to map candidate to waze link")
+		}
+		lines = append(lines, fmt.Sprintf("- %s (%s)", wazeLink.URL(), candidate.Source))
 	}
 	return message.Reply(&telegram.Reply{
-		Text: wazeLink.URL().String(),
+		Text: strings.Join(lines, "\n"),
 	})
 }
 
+// replyPlaces replies with a Waze link for every place of the list, up to placeListLimit of them, split into as many
+// messages as Telegram needs for long names.
+func replyPlaces(message *telegram.Message, list *maps.PlaceList) error {
+	places := list.Places()
+	title := list.Name()
+	if title == "" {
+		title = placeListDefaultTitle
+	}
+	header := fmt.Sprintf(placeListMessage, title, len(places))
+	if len(places) == 1 {
+		header = fmt.Sprintf(placeListSingleMessage, title)
+	}
+	lines := []string{header}
+	for i, place := range places {
+		if i == placeListLimit {
+			lines = append(lines, fmt.Sprintf(placeListMoreMessage, len(places)-placeListLimit))
+			break
+		}
+		wazeLink, err := maps.WazeFromLocation(place)
+		if err != nil {
+			return errors.Wrap(err, "failed to map place to waze link")
+		}
+		lines = append(lines, fmt.Sprintf("- %s: %s", place.Name(), wazeLink.URL()))
+	}
+	for _, text := range splitMessage(lines, telegram.MaxMessageLength) {
+		if err := message.Reply(&telegram.Reply{Text: text}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitMessage joins the lines into as few messages of at most limit characters as they fit in,
+// cutting the lines too long for a message of their own.
+func splitMessage(lines []string, limit int) []string {
+	var messages, current []string
+	length := 0
+	for _, line := range lines {
+		line = cutMessage(line, limit)
+		lineLength := messageLength(line)
+		if len(current) > 0 && length+1+lineLength > limit {
+			messages = append(messages, strings.Join(current, "\n"))
+			current, length = nil, 0
+		}
+		// Lines are joined by a newline.
+		if len(current) > 0 {
+			length++
+		}
+		current = append(current, line)
+		length += lineLength
+	}
+	if len(current) > 0 {
+		messages = append(messages, strings.Join(current, "\n"))
+	}
+	return messages
+}
+
+// messageLength counts the characters of the text the way Telegram does, in UTF-16 code units.
+func messageLength(text string) int {
+	return len(utf16.Encode([]rune(text)))
+}
+
+// cutMessage cuts the text to at most limit characters, at a rune boundary.
+func cutMessage(text string, limit int) string {
+	length := 0
+	for i, r := range text {
+		length += len(utf16.Encode([]rune{r}))
+		if length > limit {
+			return text[:i]
+		}
+	}
+	return text
+}
+
 // serverOpt is a function that modifies a http.ServeMux.
 type serverOpt func(*http.ServeMux)
 
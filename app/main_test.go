@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/pawel-ochrymowicz/google-maps-to-waze/pkg/maps"
+	"github.com/pawel-ochrymowicz/google-maps-to-waze/pkg/text"
+	"github.com/pkg/errors"
+)
+
+// TestWelcomeMessageTextExamples checks that every example of the welcome message sent as plain text,
+// rather than as a link, gets a location, so that the help text cannot drift from the parsers.
+func TestWelcomeMessageTextExamples(t *testing.T) {
+	// Short plus codes look up their locality on Google Maps, answered here with the centre of Wrocław.
+	defer func(r *maps.CachingResolver) { resolver = r }(resolver)
+	resolver = maps.NewCachingResolver(func(context.Context, *url.URL) (*maps.GoogleMapsLink, error) {
+		return maps.GoogleMapsFromLocation(maps.LatLng{Latitude: 51.1079, Longitude: 17.0385})
+	}, maps.NewLRUCache(cacheSize, cacheTTL))
+
+	_, examples, ok := strings.Cut(welcomeMessage, "Examples:\n")
+	if !ok {
+		t.Fatal("Expected examples in the welcome message")
+	}
+	tested := 0
+	for _, line := range strings.Split(strings.TrimSpace(examples), "\n") {
+		label, alternatives, ok := strings.Cut(strings.TrimPrefix(line, "- "), ": ")
+		if !ok {
+			t.Fatalf("Expected a labelled example but got %q", line)
+		}
+		for _, example := range strings.Split(alternatives, " or ") {
+			u, err := text.ParseFirstUrl(example)
+			if err != nil {
+				t.Fatalf("Failed to parse url of %q: %v", example, err)
+			}
+			// Links are parsed by the registry.
+			if u.String() != "" {
+				continue
+			}
+			tested++
+			t.Run(label+"/"+example, func(t *testing.T) {
+				location, err := textLocation(context.Background(), example)
+				if err != nil {
+					t.Fatalf("Expected a location but got error: %v", err)
+				}
+				latLng, err := location.LatLng()
+				if err != nil {
+					t.Fatalf("Expected a lat lng but got error: %v", err)
+				}
+				if !latLng.Valid() {
+					t.Errorf("Expected a valid lat lng but got %v", latLng)
+				}
+			})
+		}
+	}
+	if tested == 0 {
+		t.Error("Expected examples sent as plain text")
+	}
+}
+
+func TestTextLocation_EarliestWins(t *testing.T) {
+	testCases := []struct {
+		name             string
+		text             string
+		expectedLocation maps.Location
+	}{
+		{name: "Coordinates before plus code", text: "meet at 52.2297, 21.0122, not 9C4XGV4M+XX", expectedLocation: maps.LatLng{Latitude: 52.2297, Longitude: 21.0122}},
+		{name: "Plus code before coordinates", text: "meet at 9C4XGV4M+XX, not 52.2297, 21.0122", expectedLocation: maps.PlusCode("9C4XGV4M+XX")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			location, err := textLocation(context.Background(), tc.text)
+			if err != nil {
+				t.Fatalf("Expected no error but got %v", err)
+			}
+			if location != tc.expectedLocation {
+				t.Errorf("Expected %v but got %v", tc.expectedLocation, location)
+			}
+		})
+	}
+}
+
+func TestUnsupportedLink(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "Host not allowed", err: &maps.FetchError{URL: "https://example.com", Err: maps.ErrHostNotAllowed}, expected: true},
+		{name: "Scheme not allowed", err: &maps.FetchError{URL: "ftp://maps.google.com", Err: maps.ErrSchemeNotAllowed}, expected: true},
+		{name: "Private address", err: errors.Wrap(&maps.FetchError{URL: "http://127.0.0.1", Err: maps.ErrPrivateAddress}, "failed to resolve"), expected: true},
+		{name: "Body too large", err: &maps.FetchError{URL: "https://maps.app.goo.gl/a", Err: maps.ErrBodyTooLarge}, expected: false},
+		{name: "Too many redirects", err: &maps.FetchError{URL: "https://maps.app.goo.gl/a", Err: maps.ErrTooManyRedirects}, expected: false},
+		{name: "No error", err: nil, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := unsupportedLink(tc.err); actual != tc.expected {
+				t.Errorf("Expected %v but got %v", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package maps
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// AmapHosts are the hosts Amap, also known as Gaode, links are shared from.
+var AmapHosts = []string{"amap.com"}
+
+const (
+	// amapShortLinkHost redirects to the full links.
+	amapShortLinkHost = "surl.amap.com"
+	// amapWGS84Coordinate is the `coordinate=` value of links given in WGS84 rather than GCJ-02.
+	amapWGS84Coordinate = "wgs84"
+	amapURIHost         = "uri.amap.com"
+)
+
+// AmapLink is a location shared from Amap. Its coordinates are converted from GCJ-02 into WGS84.
+type AmapLink struct {
+	latLng LatLng
+	name   string
+}
+
+func (l *AmapLink) LatLng() (LatLng, error) {
+	return l.latLng, nil
+}
+
+// Name returns the name of the place when the link carries one.
+func (l *AmapLink) Name() string {
+	return l.name
+}
+
+// URL returns the `uri.amap.com/marker` link of the location, given in GCJ-02 as Amap expects.
+func (l *AmapLink) URL() *url.URL {
+	gcj02 := WGS84ToGCJ02(l.latLng)
+	q := url.Values{"position": {fmt.Sprintf("%f,%f", gcj02.Longitude, gcj02.Latitude)}}
+	if l.name != "" {
+		q.Set("name", l.name)
+	}
+	return &url.URL{Scheme: "https", Host: amapURIHost, Path: "/marker", RawQuery: q.Encode()}
+}
+
+// AmapFromLocation constructs AmapLink pointing at the location.
+func AmapFromLocation(l Location) (*AmapLink, error) {
+	latLng, err := l.LatLng()
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract lat lng: %w", err)
+	}
+	link := &AmapLink{latLng: latLng}
+	if named, ok := l.(interface{ Name() string }); ok {
+		link.name = named.Name()
+	}
+	return link, nil
+}
+
+// ParseAmapFromURL extracts AmapLink from the `marker?position=lng,lat` and `navigation?to=lng,lat` links of
+// `uri.amap.com` and the `?p=<poi>,lat,lng` and `?q=lat,lng` links of the Amap website.
+// Short links of `surl.amap.com` are followed to the full link with toTarget.
+func ParseAmapFromURL(ctx context.Context, u *url.URL, toTarget UrlToTarget) (*AmapLink, error) {
+	if strings.EqualFold(u.Hostname(), amapShortLinkHost) {
+		target, err := toTarget(ctx, u)
+		if err != nil {
+			return nil, fmt.Errorf("failed to follow short link: %s, error: %w", u.String(), err)
+		}
+		u = target
+	}
+	q := u.Query()
+	var (
+		latLng LatLng
+		name   string
+		ok     bool
+	)
+	switch {
+	case q.Get("position") != "":
+		latLng, _, ok = lngLatLabel(q.Get("position"))
+		name = q.Get("name")
+	case q.Get("to") != "":
+		latLng, name, ok = lngLatLabel(q.Get("to"))
+	case q.Get("p") != "":
+		// The identifier of the place comes first.
+		if _, rest, found := strings.Cut(q.Get("p"), ","); found {
+			latLng, name, ok = latLngLabel(rest)
+		}
+	case q.Get("q") != "":
+		latLng, name, ok = latLngLabel(q.Get("q"))
+	}
+	if !ok {
+		return nil, fmt.Errorf("failed to find lat lng for url: %s", u.String())
+	}
+	if !strings.EqualFold(q.Get("coordinate"), amapWGS84Coordinate) {
+		latLng = GCJ02ToWGS84(latLng)
+	}
+	return &AmapLink{latLng: latLng, name: name}, nil
+}
@@ -0,0 +1,87 @@
+package maps
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAmapFromURL(t *testing.T) {
+	httpClient := newRewriteClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/abc123" {
+			http.Redirect(w, r, "https://wb.amap.com/?p=B000A7BD6C,39.91334545536069,116.38404722455657,天安门,北京市东城区", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	beijing := LatLng{Latitude: 39.911954, Longitude: 116.377817}
+
+	testCases := []struct {
+		name           string
+		inputURL       string
+		expectedLatLng LatLng
+		expectedName   string
+		expectedError  string
+	}{
+		{
+			name:           "Marker",
+			inputURL:       "https://uri.amap.com/marker?position=116.38404722455657,39.91334545536069&name=天安门",
+			expectedLatLng: beijing,
+			expectedName:   "天安门",
+		},
+		{
+			name:           "Marker in WGS84",
+			inputURL:       "https://uri.amap.com/marker?position=116.377817,39.911954&coordinate=wgs84",
+			expectedLatLng: beijing,
+		},
+		{
+			name:           "Navigation",
+			inputURL:       "https://uri.amap.com/navigation?from=116.3,39.9,start&to=116.38404722455657,39.91334545536069,天安门&mode=car",
+			expectedLatLng: beijing,
+			expectedName:   "天安门",
+		},
+		{
+			name:           "Short link",
+			inputURL:       "https://surl.amap.com/abc123",
+			expectedLatLng: beijing,
+			expectedName:   "天安门",
+		},
+		{
+			name:          "Place without coordinates",
+			inputURL:      "https://www.amap.com/place/B000A7BD6C",
+			expectedError: "failed to find lat lng for url",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			link, err := ParseAmapFromURL(context.Background(), u, HttpGetToTarget(httpClient))
+
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			latLng, err := link.LatLng()
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expectedLatLng.Latitude, latLng.Latitude, 1e-9)
+			assert.InDelta(t, tc.expectedLatLng.Longitude, latLng.Longitude, 1e-9)
+			assert.Equal(t, tc.expectedName, link.Name())
+		})
+	}
+}
+
+func TestAmapLink_URL(t *testing.T) {
+	link, err := AmapFromLocation(LatLng{Latitude: 39.911954, Longitude: 116.377817})
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://uri.amap.com/marker?position=116.384047%2C39.913345", link.URL().String())
+}
@@ -0,0 +1,117 @@
+package maps
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// AppleMapsHosts are the hosts Apple Maps links are shared from.
+var AppleMapsHosts = []string{"maps.apple.com"}
+
+// ErrAppleMapsAddress is returned for the location of Apple Maps links giving an address rather than coordinates.
+var ErrAppleMapsAddress = errors.New("apple maps link gives an address")
+
+const (
+	// appleLabeledLatLngRegex matches the `label@lat,lng` form of the `daddr=` parameter.
+	appleLabeledLatLngRegex = `^(.*?)\s*@\s*(-?\d+(?:\.\d+)?)\s*,\s*(-?\d+(?:\.\d+)?)\s*$`
+)
+
+var appleLabeledLatLngPattern = regexp.MustCompile(appleLabeledLatLngRegex)
+
+// AppleMapsLink is a location shared from Apple Maps.
+type AppleMapsLink struct {
+	latLng LatLng
+	name   string
+	// address is the place searched for by links without coordinates.
+	address string
+}
+
+// LatLng returns the location of the link, failing with ErrAppleMapsAddress for links giving an address alone.
+func (l *AppleMapsLink) LatLng() (LatLng, error) {
+	if l.address != "" {
+		return LatLng{}, fmt.Errorf("failed to locate %q: %w", l.address, ErrAppleMapsAddress)
+	}
+	return l.latLng, nil
+}
+
+// Name returns the name of the place when the link carries one.
+func (l *AppleMapsLink) Name() string {
+	return l.name
+}
+
+// SearchURL returns the Google Maps search of the address of links giving an address alone.
+func (l *AppleMapsLink) SearchURL() (*url.URL, bool) {
+	if l.address == "" {
+		return nil, false
+	}
+	return GoogleMapsSearchURL(l.address), true
+}
+
+// ParseAppleMapsFromURL extracts AppleMapsLink from the `?ll=`, `?daddr=`, `?q=`, `?address=` and `/place?` forms
+// of Apple Maps links. Links giving an address alone carry no coordinates, so the address is kept to be searched for.
+func ParseAppleMapsFromURL(u *url.URL) (*AppleMapsLink, error) {
+	q := u.Query()
+	link := &AppleMapsLink{}
+	found := false
+	// The destination of directions wins over the pin, which wins over a search for coordinates.
+	for _, param := range []string{"daddr", "destination", "coordinate", "ll", "q"} {
+		if latLng, label, ok := appleLatLng(q.Get(param)); ok {
+			link.latLng, link.name, found = latLng, label, true
+			break
+		}
+	}
+	if link.name == "" {
+		link.name = appleName(q)
+	}
+	if !found {
+		if link.address = appleAddress(q); link.address == "" {
+			return nil, fmt.Errorf("failed to find lat lng for url: %s", u.String())
+		}
+	}
+	return link, nil
+}
+
+// appleLatLng parses `lat,lng`, `lat,lng (label)` or `label@lat,lng`.
+func appleLatLng(v string) (LatLng, string, bool) {
+	if latLng, ok := strictLatLng(v, queryLatLngPattern); ok {
+		return latLng, strings.TrimSpace(queryLatLngPattern.FindStringSubmatch(v)[3]), true
+	}
+	matches := appleLabeledLatLngPattern.FindStringSubmatch(v)
+	if matches == nil {
+		return LatLng{}, "", false
+	}
+	latLng, ok := strictLatLng(matches[2]+","+matches[3], routeStopLatLngPattern)
+	if !ok {
+		return LatLng{}, "", false
+	}
+	return latLng, matches[1], true
+}
+
+// appleName picks the name of the place, falling back to the destination, the searched text or the address.
+func appleName(q url.Values) string {
+	if name := q.Get("name"); name != "" {
+		return name
+	}
+	// The destination of directions given as text names the place the pin points at.
+	for _, param := range []string{"daddr", "destination", "q"} {
+		if v := q.Get(param); v != "" && !routeStopLatLngPattern.MatchString(v) {
+			return strings.TrimSpace(v)
+		}
+	}
+	return strings.TrimSpace(q.Get("address"))
+}
+
+// appleAddress picks the address the link points at, falling back to the destination or the searched text.
+// Text looking like coordinates is left out, as it would have been parsed were it in range.
+func appleAddress(q url.Values) string {
+	for _, param := range []string{"address", "daddr", "destination", "q"} {
+		if v := strings.TrimSpace(q.Get(param)); v != "" && !routeStopLatLngPattern.MatchString(v) {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+package maps
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAppleMapsFromURL(t *testing.T) {
+	testCases := []struct {
+		name           string
+		inputURL       string
+		expectedLatLng LatLng
+		expectedName   string
+		expectedError  string
+	}{
+		{
+			name:           "Pin with label",
+			inputURL:       "https://maps.apple.com/?ll=50.894967,4.341626&q=Atomium",
+			expectedLatLng: LatLng{Latitude: 50.894967, Longitude: 4.341626},
+			expectedName:   "Atomium",
+		},
+		{
+			name:           "Shared place with address",
+			inputURL:       "https://maps.apple.com/?address=Wystawowa%201,%2051-618%20Wroc%C5%82aw,%20Poland&auid=1234&ll=51.1069402,17.0772095&lsp=9902",
+			expectedLatLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095},
+			expectedName:   "Wystawowa 1, 51-618 Wrocław, Poland",
+		},
+		{
+			name:           "Destination coordinates",
+			inputURL:       "https://maps.apple.com/?saddr=52.2297,21.0122&daddr=51.1069402,17.0772095&dirflg=d",
+			expectedLatLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095},
+		},
+		{
+			name:           "Destination with label",
+			inputURL:       "https://maps.apple.com/?daddr=Hala+Stulecia@51.1069402,17.0772095",
+			expectedLatLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095},
+			expectedName:   "Hala Stulecia",
+		},
+		{
+			name:           "Destination label with pin",
+			inputURL:       "https://maps.apple.com/?daddr=Hala+Stulecia&ll=51.1069402,17.0772095",
+			expectedLatLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095},
+			expectedName:   "Hala Stulecia",
+		},
+		{
+			name:           "Search for coordinates",
+			inputURL:       "https://maps.apple.com/?q=51.1069402,17.0772095",
+			expectedLatLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095},
+		},
+		{
+			name:           "Place path",
+			inputURL:       "https://maps.apple.com/place?coordinate=51.1069402,17.0772095&name=Hala%20Stulecia&address=Wystawowa%201",
+			expectedLatLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095},
+			expectedName:   "Hala Stulecia",
+		},
+		{
+			name:          "Nothing to locate",
+			inputURL:      "https://maps.apple.com/?z=10&t=m",
+			expectedError: "failed to find lat lng for url",
+		},
+		{
+			name:          "Out of range destination",
+			inputURL:      "https://maps.apple.com/?daddr=117.07,51.10",
+			expectedError: "failed to find lat lng for url",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			link, err := ParseAppleMapsFromURL(u)
+
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			latLng, err := link.LatLng()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedLatLng, latLng)
+			assert.Equal(t, tc.expectedName, link.Name())
+		})
+	}
+}
+
+func TestParseAppleMapsFromURL_Address(t *testing.T) {
+	testCases := []struct {
+		name           string
+		inputURL       string
+		expectedSearch string
+	}{
+		{
+			name:           "Address only",
+			inputURL:       "https://maps.apple.com/?address=Wystawowa%201,%20Wroc%C5%82aw",
+			expectedSearch: "https://www.google.com/maps/search/?api=1&query=Wystawowa+1%2C+Wroc%C5%82aw",
+		},
+		{
+			name:           "Destination given as text",
+			inputURL:       "https://maps.apple.com/?daddr=Hala%20Stulecia,%20Wroc%C5%82aw&dirflg=d",
+			expectedSearch: "https://www.google.com/maps/search/?api=1&query=Hala+Stulecia%2C+Wroc%C5%82aw",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			link, err := ParseAppleMapsFromURL(u)
+
+			require.NoError(t, err)
+			_, err = link.LatLng()
+			assert.ErrorIs(t, err, ErrAppleMapsAddress)
+			search, ok := link.SearchURL()
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedSearch, search.String())
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package maps
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// BaiduHosts are the hosts of Baidu Maps URI API links.
+var BaiduHosts = []string{"api.map.baidu.com"}
+
+// Coordinate systems of the `coord_type=` parameter of Baidu links, BD-09 being the default.
+const (
+	baiduBD09     = "bd09ll"
+	baiduGCJ02    = "gcj02"
+	baiduWGS84    = "wgs84"
+	baiduLatLngID = "latlng:"
+	baiduNameID   = "name:"
+)
+
+// BaiduLink is a location shared from Baidu Maps. Its coordinates are converted from BD-09 into WGS84.
+type BaiduLink struct {
+	latLng LatLng
+	name   string
+}
+
+func (l *BaiduLink) LatLng() (LatLng, error) {
+	return l.latLng, nil
+}
+
+// Name returns the name of the place when the link carries one.
+func (l *BaiduLink) Name() string {
+	return l.name
+}
+
+// ParseBaiduFromURL extracts BaiduLink from the `marker?location=lat,lng`, `geocoder?location=lat,lng` and
+// `direction?destination=latlng:lat,lng|name:label` links of the Baidu Maps URI API.
+// Links given in the Mercator `bd09mc` coordinates are rejected.
+func ParseBaiduFromURL(u *url.URL) (*BaiduLink, error) {
+	q := u.Query()
+	link := &BaiduLink{}
+	found := false
+	// The destination of directions wins over the marker.
+	for _, param := range []string{"destination", "location", "latlng"} {
+		if latLng, name, ok := baiduLatLng(q.Get(param)); ok {
+			link.latLng, link.name, found = latLng, name, true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("failed to find lat lng for url: %s", u.String())
+	}
+	if link.name == "" {
+		link.name = q.Get("title")
+	}
+	switch coordType := strings.ToLower(q.Get("coord_type")); coordType {
+	case "", baiduBD09:
+		link.latLng = BD09ToWGS84(link.latLng)
+	case baiduGCJ02:
+		link.latLng = GCJ02ToWGS84(link.latLng)
+	case baiduWGS84:
+	default:
+		return nil, fmt.Errorf("unsupported coordinate type: %s of url: %s", coordType, u.String())
+	}
+	return link, nil
+}
+
+// baiduLatLng parses `lat,lng` or the `|`-separated `latlng:lat,lng` and `name:label` fields of a route stop.
+func baiduLatLng(v string) (LatLng, string, bool) {
+	if latLng, ok := strictLatLng(v, routeStopLatLngPattern); ok {
+		return latLng, "", true
+	}
+	var (
+		latLng LatLng
+		name   string
+		ok     bool
+	)
+	for _, field := range strings.Split(v, "|") {
+		switch {
+		case strings.HasPrefix(field, baiduLatLngID):
+			latLng, ok = strictLatLng(strings.TrimPrefix(field, baiduLatLngID), routeStopLatLngPattern)
+		case strings.HasPrefix(field, baiduNameID):
+			name = strings.TrimPrefix(field, baiduNameID)
+		}
+	}
+	return latLng, name, ok
+}
@@ -0,0 +1,78 @@
+package maps
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBaiduFromURL(t *testing.T) {
+	beijing := LatLng{Latitude: 39.911954, Longitude: 116.377817}
+	bd09 := GCJ02ToBD09(WGS84ToGCJ02(beijing))
+	bd09LatLng := url.QueryEscape(fmt.Sprintf("%.15f,%.15f", bd09.Latitude, bd09.Longitude))
+
+	testCases := []struct {
+		name           string
+		inputURL       string
+		expectedLatLng LatLng
+		expectedName   string
+		expectedError  string
+	}{
+		{
+			name:           "Marker",
+			inputURL:       "https://api.map.baidu.com/marker?location=" + bd09LatLng + "&title=天安门&content=北京&output=html",
+			expectedLatLng: beijing,
+			expectedName:   "天安门",
+		},
+		{
+			name:           "Marker in GCJ-02",
+			inputURL:       "https://api.map.baidu.com/marker?location=39.91334545536069,116.38404722455657&coord_type=gcj02&output=html",
+			expectedLatLng: beijing,
+		},
+		{
+			name:           "Geocoder in WGS84",
+			inputURL:       "https://api.map.baidu.com/geocoder?location=39.911954,116.377817&coord_type=wgs84&output=html",
+			expectedLatLng: beijing,
+		},
+		{
+			name:           "Direction",
+			inputURL:       "https://api.map.baidu.com/direction?origin=latlng:39.9,116.3|name:start&destination=name:天安门|latlng:" + bd09LatLng + "&mode=driving&output=html",
+			expectedLatLng: beijing,
+			expectedName:   "天安门",
+		},
+		{
+			name:          "Mercator coordinates",
+			inputURL:      "https://api.map.baidu.com/marker?location=39.9,116.4&coord_type=bd09mc",
+			expectedError: "unsupported coordinate type: bd09mc",
+		},
+		{
+			name:          "Direction by name",
+			inputURL:      "https://api.map.baidu.com/direction?destination=name:天安门&mode=driving",
+			expectedError: "failed to find lat lng for url",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			link, err := ParseBaiduFromURL(u)
+
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			latLng, err := link.LatLng()
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expectedLatLng.Latitude, latLng.Latitude, 1e-9)
+			assert.InDelta(t, tc.expectedLatLng.Longitude, latLng.Longitude, 1e-9)
+			assert.Equal(t, tc.expectedName, link.Name())
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package maps
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// BingHosts are the hosts Bing Maps links are shared from, `binged.it` being its link shortener.
+var BingHosts = []string{"bing.com", "binged.it"}
+
+const (
+	bingShortLinkHost = "binged.it"
+	// bingRoutePositionPrefix prefixes the `lat_lng_label` stops of the `rtp=` route given by coordinates,
+	// the other stops being addresses prefixed with `adr.`.
+	bingRoutePositionPrefix = "pos."
+	// bingPointPrefix prefixes the `lat_lng_label` pushpin of the `sp=` parameter.
+	bingPointPrefix    = "point."
+	bingRouteSeparator = "~"
+)
+
+// BingLink is a location shared from Bing Maps.
+type BingLink struct {
+	latLng LatLng
+	name   string
+}
+
+func (l *BingLink) LatLng() (LatLng, error) {
+	return l.latLng, nil
+}
+
+// Name returns the name of the place when the link carries one.
+func (l *BingLink) Name() string {
+	return l.name
+}
+
+// ParseBingFromURL extracts BingLink from the destination of the `rtp=pos.lat_lng~pos.lat_lng` route,
+// the `sp=point.lat_lng_label` pushpin or the `cp=lat~lng` map center of Bing Maps links.
+// Short links of `binged.it` are followed to the full link with toTarget.
+func ParseBingFromURL(ctx context.Context, u *url.URL, toTarget UrlToTarget) (*BingLink, error) {
+	if strings.EqualFold(u.Hostname(), bingShortLinkHost) {
+		target, err := toTarget(ctx, u)
+		if err != nil {
+			return nil, fmt.Errorf("failed to follow short link: %s, error: %w", u.String(), err)
+		}
+		u = target
+	}
+	q := u.Query()
+	stops := strings.Split(q.Get("rtp"), bingRouteSeparator)
+	// Only the destination counts, a route ending at an address points nowhere in particular.
+	if stop, ok := strings.CutPrefix(stops[len(stops)-1], bingRoutePositionPrefix); ok {
+		if latLng, name, ok := bingLatLng(stop); ok {
+			return &BingLink{latLng: latLng, name: name}, nil
+		}
+	}
+	if point, ok := strings.CutPrefix(q.Get("sp"), bingPointPrefix); ok {
+		if latLng, name, ok := bingLatLng(point); ok {
+			return &BingLink{latLng: latLng, name: name}, nil
+		}
+	}
+	if latLng, ok := strictLatLng(strings.Replace(q.Get("cp"), bingRouteSeparator, ",", 1), routeStopLatLngPattern); ok {
+		return &BingLink{latLng: latLng}, nil
+	}
+	return nil, fmt.Errorf("failed to find lat lng for url: %s", u.String())
+}
+
+// bingLatLng parses `lat_lng` optionally followed by `_label` and further fields.
+func bingLatLng(v string) (LatLng, string, bool) {
+	parts := strings.SplitN(v, "_", 4)
+	if len(parts) < 2 {
+		return LatLng{}, "", false
+	}
+	latLng, ok := strictLatLng(parts[0]+","+parts[1], routeStopLatLngPattern)
+	if !ok {
+		return LatLng{}, "", false
+	}
+	if len(parts) > 2 {
+		return latLng, parts[2], true
+	}
+	return latLng, "", true
+}
@@ -0,0 +1,76 @@
+package maps
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBingFromURL(t *testing.T) {
+	httpClient := newStubClient(t)
+	oldTownSquare := LatLng{Latitude: 50.08804, Longitude: 14.42076}
+
+	testCases := []struct {
+		name           string
+		inputURL       string
+		expectedLatLng LatLng
+		expectedName   string
+		expectedError  string
+	}{
+		{
+			name:           "Map center",
+			inputURL:       "https://www.bing.com/maps?cp=50.08804~14.42076&lvl=16",
+			expectedLatLng: oldTownSquare,
+		},
+		{
+			name:           "Route",
+			inputURL:       "https://www.bing.com/maps?rtp=adr.Prague%20Castle~pos.50.08804_14.42076_Old%20Town%20Square&mode=d&cp=50.09~14.41",
+			expectedLatLng: oldTownSquare,
+			expectedName:   "Old Town Square",
+		},
+		{
+			name:           "Pushpin",
+			inputURL:       "https://www.bing.com/maps?sp=point.50.08804_14.42076_Old%20Town%20Square_Prague&cp=50.1~14.4",
+			expectedLatLng: oldTownSquare,
+			expectedName:   "Old Town Square",
+		},
+		{
+			name:           "Route ending at an address",
+			inputURL:       "https://www.bing.com/maps?rtp=pos.50.1_14.4~adr.Old%20Town%20Square&cp=50.08804~14.42076",
+			expectedLatLng: oldTownSquare,
+		},
+		{
+			name:           "Short link",
+			inputURL:       "https://binged.it/3Kx9Qz1",
+			expectedLatLng: oldTownSquare,
+		},
+		{
+			name:          "Search",
+			inputURL:      "https://www.bing.com/maps?q=Old+Town+Square",
+			expectedError: "failed to find lat lng for url",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			link, err := ParseBingFromURL(context.Background(), u, HttpGetToTarget(httpClient))
+
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			latLng, err := link.LatLng()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedLatLng, latLng)
+			assert.Equal(t, tc.expectedName, link.Name())
+		})
+	}
+}
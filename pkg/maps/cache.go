@@ -0,0 +1,422 @@
+package maps
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Cache stores what short links resolved to, keyed by the link.
+// Entries expire after the TTL the cache was constructed with.
+type Cache interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry) error
+}
+
+// CacheEntry is everything a link resolved to, so that a cache hit gives the same GoogleMapsLink as the resolution did.
+type CacheEntry struct {
+	LatLng     LatLng      `json:"lat_lng"`
+	Name       string      `json:"name,omitempty"`
+	Route      *Route      `json:"route,omitempty"`
+	Candidates []Candidate `json:"candidates,omitempty"`
+	Camera     *Camera     `json:"camera,omitempty"`
+}
+
+// cacheEntry captures the link, copying the route, the candidates and the camera so that the entry does not share
+// them with the link.
+func (l *GoogleMapsLink) cacheEntry() CacheEntry {
+	return CacheEntry{
+		LatLng:     l.latLng,
+		Name:       l.name,
+		Route:      copyRoute(l.route),
+		Candidates: append([]Candidate(nil), l.candidates...),
+		Camera:     copyCamera(l.camera),
+	}
+}
+
+// googleMapsFromCacheEntry rebuilds the link the entry was captured from.
+func googleMapsFromCacheEntry(entry CacheEntry) *GoogleMapsLink {
+	return &GoogleMapsLink{
+		latLng:     entry.LatLng,
+		name:       entry.Name,
+		route:      copyRoute(entry.Route),
+		candidates: append([]Candidate(nil), entry.Candidates...),
+		camera:     copyCamera(entry.Camera),
+	}
+}
+
+// clone copies the link deeply, so that changing one copy leaves the others alone.
+func (l *GoogleMapsLink) clone() *GoogleMapsLink {
+	return googleMapsFromCacheEntry(l.cacheEntry())
+}
+
+func copyRoute(r *Route) *Route {
+	if r == nil {
+		return nil
+	}
+	route := &Route{}
+	if r.Stops != nil {
+		route.Stops = make([]RouteStop, len(r.Stops))
+	}
+	for i, stop := range r.Stops {
+		route.Stops[i] = RouteStop{Query: stop.Query}
+		if stop.LatLng != nil {
+			latLng := *stop.LatLng
+			route.Stops[i].LatLng = &latLng
+		}
+	}
+	return route
+}
+
+func copyCamera(c *Camera) *Camera {
+	if c == nil {
+		return nil
+	}
+	camera := *c
+	return &camera
+}
+
+// LRUCache is an in-memory Cache evicting the least recently used entry once full.
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	order   *list.List
+	now     func() time.Time
+}
+
+type lruEntry struct {
+	key       string
+	entry     CacheEntry
+	expiresAt time.Time
+}
+
+// NewLRUCache constructs an LRUCache holding at most size entries for ttl each.
+func NewLRUCache(size int, ttl time.Duration) *LRUCache {
+	return &LRUCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element, size),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+func (c *LRUCache) Get(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+	entry := el.Value.(*lruEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return CacheEntry{}, false
+	}
+	c.order.MoveToFront(el)
+	return entry.entry, true
+}
+
+func (c *LRUCache) Set(key string, entry CacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expiresAt := c.now().Add(c.ttl)
+	if el, ok := c.entries[key]; ok {
+		existing := el.Value.(*lruEntry)
+		existing.entry, existing.expiresAt = entry, expiresAt
+		c.order.MoveToFront(el)
+		return nil
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, entry: entry, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+	return nil
+}
+
+// FileCache is a Cache persisted as a JSON file, so that entries survive restarts.
+// Once full, the entry expiring first is evicted.
+type FileCache struct {
+	mu      sync.Mutex
+	path    string
+	size    int
+	ttl     time.Duration
+	entries map[string]fileEntry
+	now     func() time.Time
+	// version counts the changes of the entries, so that a write never replaces the file with older entries.
+	version uint64
+
+	// writeMu serializes the writes of the file, done outside of mu so that they do not block Get.
+	writeMu sync.Mutex
+	written uint64
+}
+
+// fileEntry flattens the CacheEntry into the JSON object, so that files holding only `lat_lng` still load.
+type fileEntry struct {
+	CacheEntry
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewFileCache constructs a FileCache stored at path holding at most size entries for ttl each,
+// loading the entries left there by previous runs.
+func NewFileCache(path string, size int, ttl time.Duration) (*FileCache, error) {
+	c := &FileCache{path: path, size: size, ttl: ttl, entries: map[string]fileEntry{}, now: time.Now}
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache file: %w", err)
+	}
+	if err := json.Unmarshal(raw, &c.entries); err != nil {
+		return nil, fmt.Errorf("failed to decode cache file: %w", err)
+	}
+	// Files left by runs with a larger size are trimmed as well.
+	c.evict()
+	return c, nil
+}
+
+func (c *FileCache) Get(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || !c.now().Before(entry.ExpiresAt) {
+		return CacheEntry{}, false
+	}
+	return entry.CacheEntry, true
+}
+
+func (c *FileCache) Set(key string, entry CacheEntry) error {
+	c.mu.Lock()
+	now := c.now()
+	for k, entry := range c.entries {
+		if !now.Before(entry.ExpiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = fileEntry{CacheEntry: entry, ExpiresAt: now.Add(c.ttl)}
+	c.evict()
+	c.version++
+	version := c.version
+	// The entries are never changed once stored, so a shallow copy is enough to encode them after unlocking.
+	snapshot := make(map[string]fileEntry, len(c.entries))
+	for k, entry := range c.entries {
+		snapshot[k] = entry
+	}
+	c.mu.Unlock()
+	return c.save(snapshot, version)
+}
+
+// evict drops the entries expiring first until at most size are left.
+func (c *FileCache) evict() {
+	for len(c.entries) > c.size {
+		var oldest string
+		for k, entry := range c.entries {
+			if oldest == "" || entry.ExpiresAt.Before(c.entries[oldest].ExpiresAt) {
+				oldest = k
+			}
+		}
+		delete(c.entries, oldest)
+	}
+}
+
+// save writes the entries to a temporary file first, so that a crash never leaves a truncated cache behind.
+// Entries older than the ones already written are skipped.
+func (c *FileCache) save(entries map[string]fileEntry, version uint64) error {
+	raw, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if version <= c.written {
+		return nil
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(raw); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("failed to replace cache file: %w", err)
+	}
+	c.written = version
+	return nil
+}
+
+// defaultResolveTimeout bounds the shared resolution of a link, covering every stage of the DefaultBudget.
+const defaultResolveTimeout = 30 * time.Second
+
+// ResolveFunc resolves a link to a GoogleMapsLink, e.g. RedirectResolver.Resolve.
+type ResolveFunc func(ctx context.Context, u *url.URL) (*GoogleMapsLink, error)
+
+// CacheStats counts the lookups of a CachingResolver.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	// Shared counts the misses that waited for a concurrent lookup of the same link instead of resolving it again.
+	Shared uint64
+}
+
+// CachingResolver caches the resolution of links that need fetching, such as short links.
+// Concurrent lookups of the same link collapse into a single upstream resolution.
+type CachingResolver struct {
+	resolve ResolveFunc
+	cache   Cache
+	onError func(key string, err error)
+	timeout time.Duration
+	group   flightGroup
+	hits    uint64
+	misses  uint64
+	shared  uint64
+}
+
+// CachingOpt configures a CachingResolver.
+type CachingOpt func(*CachingResolver)
+
+// WithCacheErrorHandler reports the links that resolved but failed to be cached, e.g. on a full disk.
+// Such links are returned all the same.
+func WithCacheErrorHandler(onError func(key string, err error)) CachingOpt {
+	return func(c *CachingResolver) {
+		c.onError = onError
+	}
+}
+
+// WithResolveTimeout bounds the resolution shared by the concurrent lookups of a link,
+// which goes on when some of them give up.
+func WithResolveTimeout(timeout time.Duration) CachingOpt {
+	return func(c *CachingResolver) {
+		c.timeout = timeout
+	}
+}
+
+// NewCachingResolver constructs a CachingResolver on top of resolve.
+func NewCachingResolver(resolve ResolveFunc, cache Cache, opts ...CachingOpt) *CachingResolver {
+	c := &CachingResolver{resolve: resolve, cache: cache, onError: func(string, error) {}, timeout: defaultResolveTimeout}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Resolve returns the cached location of the link, resolving and caching it on a miss.
+// Links carrying their location are resolved right away without touching the cache.
+func (c *CachingResolver) Resolve(ctx context.Context, u *url.URL) (*GoogleMapsLink, error) {
+	if link, ok := googleMapsFromURL(u); ok {
+		return link, nil
+	}
+	key := u.String()
+	if entry, ok := c.cache.Get(key); ok {
+		atomic.AddUint64(&c.hits, 1)
+		return googleMapsFromCacheEntry(entry), nil
+	}
+	atomic.AddUint64(&c.misses, 1)
+	link, err, shared := c.group.do(ctx, key, c.timeout, func(ctx context.Context) (*GoogleMapsLink, error) {
+		link, err := c.resolve(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.cache.Set(key, link.cacheEntry()); err != nil {
+			c.onError(key, fmt.Errorf("failed to cache url: %s, error: %w", key, err))
+		}
+		return link, nil
+	})
+	if shared {
+		atomic.AddUint64(&c.shared, 1)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Every caller waiting on the call gets a copy of its own.
+	return link.clone(), nil
+}
+
+// Stats returns the lookup counts so far.
+func (c *CachingResolver) Stats() CacheStats {
+	return CacheStats{
+		Hits:   atomic.LoadUint64(&c.hits),
+		Misses: atomic.LoadUint64(&c.misses),
+		Shared: atomic.LoadUint64(&c.shared),
+	}
+}
+
+// flightGroup runs a single call per key at a time, handing its result to every caller waiting on the key.
+// The call runs on its own context, so that no single caller giving up cancels it for the others.
+// It is cancelled once every caller has given up, or when its timeout runs out.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	link   *GoogleMapsLink
+	err    error
+	// waiters counts the callers waiting for the call, the one that started it included.
+	waiters int
+}
+
+func (g *flightGroup) do(ctx context.Context, key string, timeout time.Duration, fn func(ctx context.Context) (*GoogleMapsLink, error)) (*GoogleMapsLink, error, bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*flightCall{}
+	}
+	call, shared := g.calls[key]
+	if !shared {
+		callCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		call = &flightCall{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = call
+		go func() {
+			defer cancel()
+			link, err := fn(callCtx)
+			g.mu.Lock()
+			g.forget(key, call)
+			g.mu.Unlock()
+			call.link, call.err = link, err
+			close(call.done)
+		}()
+	}
+	call.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.link, call.err, shared
+	case <-ctx.Done():
+		g.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			g.forget(key, call)
+			call.cancel()
+		}
+		g.mu.Unlock()
+		return nil, context.Cause(ctx), shared
+	}
+}
+
+// forget removes the call from the group unless a newer call of the key replaced it already.
+func (g *flightGroup) forget(key string, call *flightCall) {
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
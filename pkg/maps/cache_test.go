@@ -0,0 +1,346 @@
+package maps
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	warsaw  = LatLng{Latitude: 52.2297, Longitude: 21.0122}
+	wroclaw = LatLng{Latitude: 51.107885, Longitude: 17.038538}
+)
+
+func TestLRUCache(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewLRUCache(2, time.Hour)
+	cache.now = func() time.Time { return now }
+
+	require.NoError(t, cache.Set("a", CacheEntry{LatLng: warsaw}))
+	require.NoError(t, cache.Set("b", CacheEntry{LatLng: wroclaw}))
+	_, ok := cache.Get("a")
+	require.True(t, ok)
+
+	// "b" is the least recently used entry now.
+	require.NoError(t, cache.Set("c", CacheEntry{LatLng: warsaw}))
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	entry, ok := cache.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, warsaw, entry.LatLng)
+
+	now = now.Add(time.Hour)
+	_, ok = cache.Get("a")
+	assert.False(t, ok)
+}
+
+func TestFileCache(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	cache, err := NewFileCache(path, 10, time.Hour)
+	require.NoError(t, err)
+	cache.now = func() time.Time { return now }
+	require.NoError(t, cache.Set("https://maps.app.goo.gl/a", CacheEntry{LatLng: warsaw}))
+
+	// A new instance picks up the entries of the previous one.
+	reopened, err := NewFileCache(path, 10, time.Hour)
+	require.NoError(t, err)
+	reopened.now = func() time.Time { return now.Add(time.Minute) }
+	entry, ok := reopened.Get("https://maps.app.goo.gl/a")
+	require.True(t, ok)
+	assert.Equal(t, CacheEntry{LatLng: warsaw}, entry)
+
+	reopened.now = func() time.Time { return now.Add(time.Hour) }
+	_, ok = reopened.Get("https://maps.app.goo.gl/a")
+	assert.False(t, ok)
+}
+
+func TestFileCache_EvictsEntryExpiringFirst(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	cache, err := NewFileCache(path, 2, time.Hour)
+	require.NoError(t, err)
+	for i, key := range []string{"a", "b", "c"} {
+		cache.now = func() time.Time { return now.Add(time.Duration(i) * time.Minute) }
+		require.NoError(t, cache.Set(key, CacheEntry{LatLng: warsaw}))
+	}
+	_, ok := cache.Get("a")
+	assert.False(t, ok)
+
+	// A smaller cache trims the file on load.
+	reopened, err := NewFileCache(path, 1, time.Hour)
+	require.NoError(t, err)
+	reopened.now = cache.now
+	_, ok = reopened.Get("b")
+	assert.False(t, ok)
+	_, ok = reopened.Get("c")
+	assert.True(t, ok)
+}
+
+func TestCachingResolver(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	resolver := NewCachingResolver(func(ctx context.Context, u *url.URL) (*GoogleMapsLink, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return &GoogleMapsLink{latLng: wroclaw}, nil
+	}, NewLRUCache(10, time.Hour))
+
+	u, err := url.Parse("https://maps.app.goo.gl/LsERZt5ZbvMPqm92A")
+	require.NoError(t, err)
+
+	const concurrency = 5
+	var wg sync.WaitGroup
+	results := make([]*GoogleMapsLink, concurrency)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			link, err := resolver.Resolve(context.Background(), u)
+			assert.NoError(t, err)
+			results[i] = link
+		}(i)
+	}
+	// Wait for every goroutine to join the call in flight.
+	require.Eventually(t, func() bool {
+		resolver.group.mu.Lock()
+		defer resolver.group.mu.Unlock()
+		call, ok := resolver.group.calls[u.String()]
+		return ok && call.waiters == concurrency
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for i, link := range results {
+		require.NotNil(t, link)
+		assert.Equal(t, wroclaw, link.latLng)
+		for _, other := range results[:i] {
+			assert.NotSame(t, other, link)
+		}
+	}
+
+	link, err := resolver.Resolve(context.Background(), u)
+	require.NoError(t, err)
+	assert.Equal(t, wroclaw, link.latLng)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, CacheStats{Hits: 1, Misses: concurrency, Shared: concurrency - 1}, resolver.Stats())
+}
+
+func TestFileCache_LatLngOnlyEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	raw := `{"https://maps.app.goo.gl/a":{"lat_lng":{"Latitude":52.2297,"Longitude":21.0122},"expires_at":"2024-01-01T01:00:00Z"}}`
+	require.NoError(t, os.WriteFile(path, []byte(raw), 0o600))
+
+	cache, err := NewFileCache(path, 10, time.Hour)
+	require.NoError(t, err)
+	cache.now = func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	entry, ok := cache.Get("https://maps.app.goo.gl/a")
+	require.True(t, ok)
+	assert.Equal(t, CacheEntry{LatLng: warsaw}, entry)
+}
+
+func TestCachingResolver_CancelledCaller(t *testing.T) {
+	release := make(chan struct{})
+	resolver := NewCachingResolver(func(ctx context.Context, u *url.URL) (*GoogleMapsLink, error) {
+		select {
+		case <-release:
+			return &GoogleMapsLink{latLng: wroclaw}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}, NewLRUCache(10, time.Hour))
+	u, err := url.Parse("https://maps.app.goo.gl/LsERZt5ZbvMPqm92A")
+	require.NoError(t, err)
+
+	// The caller starting the resolution gives up while another one waits for it.
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error)
+	go func() {
+		_, err := resolver.Resolve(ctx, u)
+		leaderErr <- err
+	}()
+	waiterLink := make(chan *GoogleMapsLink)
+	go func() {
+		link, err := resolver.Resolve(context.Background(), u)
+		assert.NoError(t, err)
+		waiterLink <- link
+	}()
+	require.Eventually(t, func() bool {
+		resolver.group.mu.Lock()
+		defer resolver.group.mu.Unlock()
+		call, ok := resolver.group.calls[u.String()]
+		return ok && call.waiters == 2
+	}, time.Second, time.Millisecond)
+	cancel()
+
+	assert.ErrorIs(t, <-leaderErr, context.Canceled)
+	// The resolution goes on for the caller still waiting.
+	close(release)
+	link := <-waiterLink
+	require.NotNil(t, link)
+	assert.Equal(t, wroclaw, link.latLng)
+}
+
+func TestCachingResolver_AllCallersCancelled(t *testing.T) {
+	cancelled := make(chan struct{})
+	resolver := NewCachingResolver(func(ctx context.Context, u *url.URL) (*GoogleMapsLink, error) {
+		<-ctx.Done()
+		close(cancelled)
+		return nil, ctx.Err()
+	}, NewLRUCache(10, time.Hour))
+	u, err := url.Parse("https://maps.app.goo.gl/LsERZt5ZbvMPqm92A")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = resolver.Resolve(ctx, u)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("resolution left running after every caller gave up")
+	}
+}
+
+func TestCachingResolver_HitKeepsLink(t *testing.T) {
+	route := &Route{Stops: []RouteStop{{Query: "Warszawa", LatLng: &warsaw}, {Query: "Wrocław", LatLng: &wroclaw}}}
+	resolved := &GoogleMapsLink{
+		latLng: wroclaw,
+		name:   "Wrocław",
+		route:  route,
+		candidates: []Candidate{
+			{LatLng: wroclaw, Source: SourceRedirect, Confidence: 0.9},
+			{LatLng: warsaw, Source: SourceBody, Confidence: 0.85},
+		},
+	}
+	path := filepath.Join(t.TempDir(), "cache.json")
+	cache, err := NewFileCache(path, 10, time.Hour)
+	require.NoError(t, err)
+	resolver := NewCachingResolver(func(ctx context.Context, u *url.URL) (*GoogleMapsLink, error) {
+		return resolved, nil
+	}, cache)
+	u, err := url.Parse("https://maps.app.goo.gl/route")
+	require.NoError(t, err)
+	_, err = resolver.Resolve(context.Background(), u)
+	require.NoError(t, err)
+
+	// A new instance reads the entry back from the file.
+	reopened, err := NewFileCache(path, 10, time.Hour)
+	require.NoError(t, err)
+	resolver = NewCachingResolver(func(ctx context.Context, u *url.URL) (*GoogleMapsLink, error) {
+		t.Fatal("unexpected resolution")
+		return nil, nil
+	}, reopened)
+	link, err := resolver.Resolve(context.Background(), u)
+
+	require.NoError(t, err)
+	assert.Equal(t, resolved, link)
+	assert.True(t, link.Ambiguous())
+}
+
+func TestCachingResolver_ChangedLinkLeavesCache(t *testing.T) {
+	resolver := NewCachingResolver(func(ctx context.Context, u *url.URL) (*GoogleMapsLink, error) {
+		route := &Route{Stops: []RouteStop{{Query: "Warszawa", LatLng: &LatLng{Latitude: warsaw.Latitude, Longitude: warsaw.Longitude}}, {Query: "Wrocław"}}}
+		return &GoogleMapsLink{latLng: wroclaw, route: route}, nil
+	}, NewLRUCache(10, time.Hour))
+	u, err := url.Parse("https://maps.app.goo.gl/route")
+	require.NoError(t, err)
+	expected := &Route{Stops: []RouteStop{{Query: "Warszawa", LatLng: &LatLng{Latitude: warsaw.Latitude, Longitude: warsaw.Longitude}}, {Query: "Wrocław"}}}
+
+	for i := 0; i < 3; i++ {
+		link, err := resolver.Resolve(context.Background(), u)
+		require.NoError(t, err)
+		require.Equal(t, expected, link.Route())
+
+		// Changing the route of a link given out leaves the links of later hits alone.
+		stops := link.Route().Stops
+		stops[0].Query = "Kraków"
+		stops[0].LatLng.Latitude = 50.0647
+		stops[1].LatLng = &LatLng{Latitude: 0, Longitude: 0}
+		link.Route().Stops = stops[:1]
+	}
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 1}, resolver.Stats())
+}
+
+func TestCachingResolver_HitKeepsCamera(t *testing.T) {
+	resolved := &GoogleMapsLink{
+		latLng: LatLng{Latitude: 51.1068963, Longitude: 17.0777458},
+		camera: &Camera{Kind: CameraStreetView, LatLng: LatLng{Latitude: 51.1068963, Longitude: 17.0777458}, Heading: 112.5, Pitch: 5},
+	}
+	path := filepath.Join(t.TempDir(), "cache.json")
+	cache, err := NewFileCache(path, 10, time.Hour)
+	require.NoError(t, err)
+	resolver := NewCachingResolver(func(ctx context.Context, u *url.URL) (*GoogleMapsLink, error) {
+		return resolved, nil
+	}, cache)
+	u, err := url.Parse("https://maps.app.goo.gl/pano")
+	require.NoError(t, err)
+	_, err = resolver.Resolve(context.Background(), u)
+	require.NoError(t, err)
+
+	reopened, err := NewFileCache(path, 10, time.Hour)
+	require.NoError(t, err)
+	resolver = NewCachingResolver(func(ctx context.Context, u *url.URL) (*GoogleMapsLink, error) {
+		t.Fatal("unexpected resolution")
+		return nil, nil
+	}, reopened)
+	link, err := resolver.Resolve(context.Background(), u)
+
+	require.NoError(t, err)
+	require.NotNil(t, link.Camera())
+	assert.Equal(t, *resolved.camera, *link.Camera())
+	assert.NotSame(t, resolved.camera, link.Camera())
+}
+
+// failingCache fails to store any entry, like a cache file on a full disk.
+type failingCache struct{}
+
+func (failingCache) Get(string) (CacheEntry, bool) { return CacheEntry{}, false }
+
+func (failingCache) Set(string, CacheEntry) error { return errors.New("no space left on device") }
+
+func TestCachingResolver_CacheFailure(t *testing.T) {
+	var failures []string
+	resolver := NewCachingResolver(func(ctx context.Context, u *url.URL) (*GoogleMapsLink, error) {
+		return &GoogleMapsLink{latLng: wroclaw}, nil
+	}, failingCache{}, WithCacheErrorHandler(func(key string, err error) {
+		failures = append(failures, key)
+		assert.ErrorContains(t, err, "no space left on device")
+	}))
+	u, err := url.Parse("https://maps.app.goo.gl/LsERZt5ZbvMPqm92A")
+	require.NoError(t, err)
+
+	link, err := resolver.Resolve(context.Background(), u)
+
+	require.NoError(t, err)
+	assert.Equal(t, wroclaw, link.latLng)
+	assert.Equal(t, []string{u.String()}, failures)
+}
+
+func TestCachingResolver_SkipsLinksWithLocation(t *testing.T) {
+	resolver := NewCachingResolver(func(ctx context.Context, u *url.URL) (*GoogleMapsLink, error) {
+		t.Fatal("unexpected resolution")
+		return nil, nil
+	}, NewLRUCache(10, time.Hour))
+	u, err := url.Parse("https://maps.google.com/?q=51.107885,17.038538")
+	require.NoError(t, err)
+
+	link, err := resolver.Resolve(context.Background(), u)
+	require.NoError(t, err)
+	assert.Equal(t, wroclaw, link.latLng)
+	assert.Equal(t, CacheStats{}, resolver.Stats())
+}
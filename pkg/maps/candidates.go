@@ -0,0 +1,150 @@
+package maps
+
+import (
+	"math"
+	"sort"
+)
+
+// Source tells where a location candidate was found.
+type Source string
+
+const (
+	// SourcePath is a `lat,lng` pair in the path of the link.
+	SourcePath Source = "path"
+	// SourceQuery is a query parameter of the link, e.g. `q=` or `destination=`.
+	SourceQuery Source = "query"
+	// SourceData is an entry of the `data=` parameter, e.g. the place pin.
+	SourceData Source = "data"
+	// SourceRedirect is a hop of the redirect chain of a short link.
+	SourceRedirect Source = "redirect"
+	// SourceBody is the content of the page the link leads to.
+	SourceBody Source = "body"
+)
+
+// Confidence of the candidates by the form they were found in, from the place pin down to a stray number.
+const (
+	confidencePin       = 0.95
+	confidenceQuery     = 0.9
+	confidenceRoute     = 0.9
+	confidenceSearch    = 0.85
+	confidenceViewport  = 0.6
+	confidenceMapView   = 0.5
+	confidenceBodyPin   = 0.6
+	confidenceBodyView  = 0.4
+	confidenceBodyState = 0.3
+	confidencePath      = 0.4
+	// swappedPenalty scales the confidence down when latitude and longitude had to be swapped.
+	swappedPenalty = 0.5
+	// redirectPenalty scales the confidence down for candidates found in a redirect hop rather than in the link.
+	redirectPenalty = 0.95
+	// ambiguityMargin is the confidence difference below which two distinct candidates are ambiguous.
+	ambiguityMargin = 0.15
+	// sameLocationDegrees is the distance, roughly 10 metres, below which two candidates are the same location.
+	sameLocationDegrees = 1e-4
+	// nearbyDegrees is the distance, roughly 100 metres, below which two candidates lead to the same place,
+	// such as the marker of a place page and the center of its map, so that they are never ambiguous.
+	nearbyDegrees = 1e-3
+)
+
+// Candidate is a possible location of a link along with where it was found and how much it can be trusted.
+type Candidate struct {
+	LatLng     LatLng
+	Source     Source
+	Confidence float64
+	// Swapped tells that latitude and longitude were found in reversed order and swapped.
+	Swapped bool
+}
+
+func newCandidate(latLng LatLng, source Source, confidence float64, swapped bool) Candidate {
+	if swapped {
+		confidence *= swappedPenalty
+	}
+	return Candidate{LatLng: latLng, Source: source, Confidence: confidence, Swapped: swapped}
+}
+
+func (c Candidate) sameLocation(other Candidate) bool {
+	return c.within(other, sameLocationDegrees)
+}
+
+func (c Candidate) nearby(other Candidate) bool {
+	return c.within(other, nearbyDegrees)
+}
+
+func (c Candidate) within(other Candidate, degrees float64) bool {
+	return math.Abs(c.LatLng.Latitude-other.LatLng.Latitude) < degrees &&
+		math.Abs(c.LatLng.Longitude-other.LatLng.Longitude) < degrees
+}
+
+// newCandidateLink constructs a GoogleMapsLink pointing at its single candidate.
+func newCandidateLink(c Candidate) *GoogleMapsLink {
+	return &GoogleMapsLink{latLng: c.LatLng, candidates: []Candidate{c}}
+}
+
+// mergeLinks ranks the candidates of all links by confidence, keeping the best one of every location.
+// The name and the route are taken from the first link carrying them.
+func mergeLinks(links []*GoogleMapsLink) (*GoogleMapsLink, bool) {
+	if len(links) == 0 {
+		return nil, false
+	}
+	merged := &GoogleMapsLink{}
+	var candidates []Candidate
+	for _, link := range links {
+		candidates = append(candidates, link.candidates...)
+		if merged.name == "" {
+			merged.name = link.name
+		}
+		if merged.route == nil {
+			merged.route = link.route
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Confidence > candidates[j].Confidence
+	})
+	for _, c := range candidates {
+		duplicate := false
+		for _, kept := range merged.candidates {
+			if kept.sameLocation(c) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			merged.candidates = append(merged.candidates, c)
+		}
+	}
+	merged.latLng = merged.candidates[0].LatLng
+	return merged, true
+}
+
+// withSource returns a copy of the link with every candidate attributed to the given source, scaling its confidence.
+// The link itself is left untouched, as it may be handed to other callers as well.
+func (l *GoogleMapsLink) withSource(source Source, penalty float64) *GoogleMapsLink {
+	link := *l
+	link.candidates = make([]Candidate, len(l.candidates))
+	for i, c := range l.candidates {
+		c.Source = source
+		c.Confidence *= penalty
+		link.candidates[i] = c
+	}
+	return &link
+}
+
+// Candidates returns every location found for the link, the most trustworthy first.
+func (l *GoogleMapsLink) Candidates() []Candidate {
+	return l.candidates
+}
+
+// Ambiguous tells whether the link points at distinct locations with a similar confidence,
+// in which case the user is better asked which one was meant. Candidates near the best one do not count as distinct.
+func (l *GoogleMapsLink) Ambiguous() bool {
+	if len(l.candidates) < 2 {
+		return false
+	}
+	best := l.candidates[0]
+	for _, c := range l.candidates[1:] {
+		if !best.nearby(c) {
+			return best.Confidence-c.Confidence < ambiguityMargin
+		}
+	}
+	return false
+}
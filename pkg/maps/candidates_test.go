@@ -0,0 +1,149 @@
+package maps
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoogleMapsFromURL_Candidates(t *testing.T) {
+	testCases := []struct {
+		name               string
+		inputURL           string
+		expectedCandidates []Candidate
+		expectedAmbiguous  bool
+	}{
+		{
+			name:     "Pin outranks viewport",
+			inputURL: "https://www.google.com/maps/place/Hala+Stulecia/@51.1,17.0,15z/data=!4m5!3m4!1s0x0:0x1!8m2!3d51.1069402!4d17.0772095",
+			expectedCandidates: []Candidate{
+				{LatLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095}, Source: SourceData, Confidence: confidencePin},
+				{LatLng: LatLng{Latitude: 51.1, Longitude: 17.0}, Source: SourcePath, Confidence: confidencePath},
+			},
+		},
+		{
+			name:     "Query pin outranks ll viewport",
+			inputURL: "https://maps.google.com/maps?ll=50.0,19.0&q=52.2,21.0&z=14",
+			expectedCandidates: []Candidate{
+				{LatLng: LatLng{Latitude: 52.2, Longitude: 21.0}, Source: SourceQuery, Confidence: confidenceQuery},
+				{LatLng: LatLng{Latitude: 50.0, Longitude: 19.0}, Source: SourceQuery, Confidence: confidenceViewport},
+			},
+		},
+		{
+			name:     "Same location found twice",
+			inputURL: "https://www.google.com/maps/search/?api=1&query=52.2,21.0&q=52.20001,21.00001",
+			expectedCandidates: []Candidate{
+				{LatLng: LatLng{Latitude: 52.20001, Longitude: 21.00001}, Source: SourceQuery, Confidence: confidenceQuery},
+			},
+		},
+		{
+			name:     "Conflicting queries",
+			inputURL: "https://www.google.com/maps/search/?api=1&query=52.2,21.0&q=50.0,19.0",
+			expectedCandidates: []Candidate{
+				{LatLng: LatLng{Latitude: 50.0, Longitude: 19.0}, Source: SourceQuery, Confidence: confidenceQuery},
+				{LatLng: LatLng{Latitude: 52.2, Longitude: 21.0}, Source: SourceQuery, Confidence: confidenceQuery},
+			},
+			expectedAmbiguous: true,
+		},
+		{
+			name:     "Swapped path",
+			inputURL: "https://www.google.com/maps/place/107.2161305,-2.4033934",
+			expectedCandidates: []Candidate{
+				{LatLng: LatLng{Latitude: -2.4033934, Longitude: 107.2161305}, Source: SourcePath, Confidence: confidencePath * swappedPenalty, Swapped: true},
+			},
+		},
+		{
+			name:     "Route destination resolved from data",
+			inputURL: "https://www.google.com/maps/dir/Warsaw/Wroc%C5%82aw+Rynek/@51.6,19.0,7z/data=!4m13!4m12!1m5!1m1!1s0x0:0x1!2m2!1d21.0122!2d52.2297!1m5!1m1!1s0x0:0x2!2m2!1d17.0320!2d51.1098",
+			expectedCandidates: []Candidate{
+				{LatLng: LatLng{Latitude: 51.1098, Longitude: 17.0320}, Source: SourceData, Confidence: confidenceRoute},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			link, ok := googleMapsFromURL(u)
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedCandidates, link.Candidates())
+			assert.Equal(t, tc.expectedAmbiguous, link.Ambiguous())
+			latLng, err := link.LatLng()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCandidates[0].LatLng, latLng)
+		})
+	}
+}
+
+func TestGoogleMapsFromContent_Candidates(t *testing.T) {
+	content := `<a href="/maps/place/Hala+Stulecia/@51.1069402,17.0772095,17z">` +
+		`<img src="https://maps.google.com/maps/api/staticmap?center=51.2%2C17.1&zoom=15">`
+
+	link, ok := googleMapsFromContent(content)
+
+	require.True(t, ok)
+	assert.Equal(t, []Candidate{
+		{LatLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095}, Source: SourceBody, Confidence: confidenceBodyPin},
+		{LatLng: LatLng{Latitude: 51.2, Longitude: 17.1}, Source: SourceBody, Confidence: confidenceBodyView},
+	}, link.Candidates())
+	assert.False(t, link.Ambiguous())
+}
+
+func TestGoogleMapsFromContent_NearbyCandidates(t *testing.T) {
+	// The static map of a place page is centered a few dozen metres off the marker of its state.
+	content := `<img src="https://maps.google.com/maps/api/staticmap?center=51.1072%2C17.0769&zoom=15">` +
+		`[null,null,51.1069402,17.0772095]`
+
+	link, ok := googleMapsFromContent(content)
+
+	require.True(t, ok)
+	assert.Len(t, link.Candidates(), 2)
+	assert.False(t, link.Ambiguous())
+}
+
+func TestGoogleMapsLink_Ambiguous(t *testing.T) {
+	hala := LatLng{Latitude: 51.1069402, Longitude: 17.0772095}
+	nearHala := LatLng{Latitude: 51.1072, Longitude: 17.0769}
+	rynek := LatLng{Latitude: 51.1098, Longitude: 17.0320}
+
+	link := &GoogleMapsLink{latLng: hala, candidates: []Candidate{
+		{LatLng: hala, Confidence: confidenceBodyView},
+		{LatLng: nearHala, Confidence: confidenceBodyView},
+		{LatLng: rynek, Confidence: confidenceBodyState},
+	}}
+
+	// The nearby candidate is skipped, the distinct one being compared instead.
+	assert.True(t, link.Ambiguous())
+	link.candidates[2].Confidence = confidenceBodyState - ambiguityMargin
+	assert.False(t, link.Ambiguous())
+}
+
+func TestGoogleMapsLink_WithSource(t *testing.T) {
+	link := &GoogleMapsLink{latLng: warsaw, candidates: []Candidate{{LatLng: warsaw, Source: SourceQuery, Confidence: confidenceQuery}}}
+
+	redirected := link.withSource(SourceRedirect, redirectPenalty)
+
+	assert.Equal(t, []Candidate{{LatLng: warsaw, Source: SourceRedirect, Confidence: confidenceQuery * redirectPenalty}}, redirected.Candidates())
+	assert.Equal(t, []Candidate{{LatLng: warsaw, Source: SourceQuery, Confidence: confidenceQuery}}, link.Candidates())
+}
+
+func TestRedirectResolver_Resolve_RedirectCandidates(t *testing.T) {
+	httpClient := newRewriteClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://maps.google.com/maps?q=51.1069402,17.0772095", http.StatusFound)
+	}))
+	u, err := url.Parse("https://maps.app.goo.gl/pin")
+	require.NoError(t, err)
+
+	link, err := NewRedirectResolver(httpClient).Resolve(context.Background(), u)
+
+	require.NoError(t, err)
+	assert.Equal(t, []Candidate{
+		{LatLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095}, Source: SourceRedirect, Confidence: confidenceQuery * redirectPenalty},
+	}, link.Candidates())
+}
@@ -0,0 +1,100 @@
+package maps
+
+import (
+	"github.com/pkg/errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrConsentRequired is returned when Google keeps redirecting to its consent page despite the consent cookie.
+var ErrConsentRequired = errors.New("google consent required")
+
+const (
+	// defaultConsentCookie rejects the optional cookies, which is enough for Google to skip the consent page.
+	defaultConsentCookie = "SOCS=CAI"
+	consentHostPrefix    = "consent."
+	// consentYouTubeDomain is the domain of the consent page of YouTube, next to the Google domains of every country.
+	consentYouTubeDomain = "youtube.com"
+	consentContinueParam = "continue"
+)
+
+// browserHeaders make the fallback request look like it comes from a regular browser.
+var browserHeaders = map[string]string{
+	"User-Agent":      "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36",
+	"Accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+	"Accept-Language": "en-US,en;q=0.9",
+}
+
+// HttpOpt configures the requests made by HttpGetToInput.
+type HttpOpt func(*httpOpts)
+
+type httpOpts struct {
+	consentCookie string
+	header        http.Header
+}
+
+func newHttpOpts(opts ...HttpOpt) *httpOpts {
+	o := &httpOpts{consentCookie: defaultConsentCookie, header: http.Header{}}
+	for k, v := range browserHeaders {
+		o.header.Set(k, v)
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithConsentCookie sets the cookie sent when a request ends up on the Google consent page, e.g. `SOCS=CAI`.
+func WithConsentCookie(cookie string) HttpOpt {
+	return func(o *httpOpts) {
+		if cookie != "" {
+			o.consentCookie = cookie
+		}
+	}
+}
+
+// WithHeader overrides one of the browser-like headers sent along with the consent cookie.
+func WithHeader(key, value string) HttpOpt {
+	return func(o *httpOpts) {
+		o.header.Set(key, value)
+	}
+}
+
+// withConsent adds the consent cookie and the browser-like headers to the request.
+func (o *httpOpts) withConsent(req *http.Request) {
+	for k := range o.header {
+		req.Header.Set(k, o.header.Get(k))
+	}
+	req.Header.Set("Cookie", o.consentCookie)
+}
+
+// consentTarget tells whether the response is, or redirects to, the Google consent page
+// and returns the page the user was heading to from its `continue=` parameter.
+func consentTarget(resp *http.Response) (*url.URL, bool) {
+	if resp.Request != nil && isConsentURL(resp.Request.URL) {
+		return consentContinue(resp.Request.URL)
+	}
+	if location, err := resp.Location(); err == nil && isConsentURL(location) {
+		return consentContinue(location)
+	}
+	return nil, false
+}
+
+// isConsentURL tells whether the URL is on a consent page of Google, e.g. `consent.google.pl`,
+// so that only Google gets to pick the page followed from its `continue=` parameter.
+func isConsentURL(u *url.URL) bool {
+	if u == nil {
+		return false
+	}
+	domain, ok := strings.CutPrefix(strings.ToLower(u.Hostname()), consentHostPrefix)
+	return ok && (domain == consentYouTubeDomain || googleHostPattern.MatchString(domain))
+}
+
+func consentContinue(u *url.URL) (*url.URL, bool) {
+	target, err := url.Parse(u.Query().Get(consentContinueParam))
+	if err != nil || !target.IsAbs() {
+		return nil, false
+	}
+	return target, true
+}
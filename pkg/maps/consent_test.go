@@ -0,0 +1,156 @@
+package maps
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hostRewriteTransport sends every request to the test server, keeping the original host in the Host header.
+type hostRewriteTransport struct {
+	target *url.URL
+}
+
+func (t *hostRewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	rewritten := r.Clone(r.Context())
+	rewritten.URL.Scheme = t.target.Scheme
+	rewritten.URL.Host = t.target.Host
+	rewritten.Host = r.URL.Host
+	resp, err := http.DefaultTransport.RoundTrip(rewritten)
+	if err != nil {
+		return nil, err
+	}
+	resp.Request = r
+	return resp, nil
+}
+
+func newRewriteClient(t *testing.T, handler http.Handler) *http.Client {
+	testServer := httptest.NewServer(handler)
+	t.Cleanup(testServer.Close)
+	target, err := url.Parse(testServer.URL)
+	require.NoError(t, err)
+	return &http.Client{Transport: &hostRewriteTransport{target: target}}
+}
+
+const consentRedirect = "https://consent.google.com/ml?continue=https://www.google.com/maps/place/Hala%2BStulecia&gl=PL&hl=en"
+
+func consentHandler(requiredCookie string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Host {
+		case "maps.app.goo.gl":
+			http.Redirect(w, r, consentRedirect, http.StatusFound)
+		case "consent.google.com":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("<html>Before you continue to Google</html>"))
+		case "www.google.com":
+			if !strings.Contains(r.Header.Get("Cookie"), requiredCookie) || r.Header.Get("User-Agent") == "" {
+				http.Redirect(w, r, consentRedirect, http.StatusFound)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`<a href="/maps/place/Hala+Stulecia/@51.1069402,17.0772095,17z">`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+func TestHttpGetToInput_Consent(t *testing.T) {
+	testCases := []struct {
+		name           string
+		requiredCookie string
+		opts           []HttpOpt
+		expectedError  error
+	}{
+		{
+			name:           "Default consent cookie",
+			requiredCookie: defaultConsentCookie,
+		},
+		{
+			name:           "Configured consent cookie",
+			requiredCookie: "SOCS=CAESEwgDEgk",
+			opts:           []HttpOpt{WithConsentCookie("SOCS=CAESEwgDEgk")},
+		},
+		{
+			name:           "Rejected consent cookie",
+			requiredCookie: "SOCS=CAESEwgDEgk",
+			expectedError:  ErrConsentRequired,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpClient := newRewriteClient(t, consentHandler(tc.requiredCookie))
+			u, err := url.Parse("https://maps.app.goo.gl/LsERZt5ZbvMPqm92A")
+			require.NoError(t, err)
+
+			content, err := HttpGetToInput(httpClient, tc.opts...)(context.Background(), u)
+			if tc.expectedError != nil {
+				require.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, content, "@51.1069402,17.0772095")
+		})
+	}
+}
+
+func TestConsentTarget_Redirect(t *testing.T) {
+	location, err := url.Parse(consentRedirect)
+	require.NoError(t, err)
+	resp := &http.Response{
+		StatusCode: http.StatusFound,
+		Header:     http.Header{"Location": {location.String()}},
+		Request:    &http.Request{URL: &url.URL{Scheme: "https", Host: "maps.app.goo.gl", Path: "/abc"}},
+	}
+
+	target, ok := consentTarget(resp)
+	require.True(t, ok)
+	assert.Equal(t, "https://www.google.com/maps/place/Hala+Stulecia", target.String())
+}
+
+func TestConsentTarget_OtherHosts(t *testing.T) {
+	testCases := []struct {
+		name       string
+		location   string
+		expectedOk bool
+	}{
+		{
+			name:       "Google consent of a country",
+			location:   "https://consent.google.pl/ml?continue=https://www.google.pl/maps/place/Hala%2BStulecia",
+			expectedOk: true,
+		},
+		{
+			name:       "YouTube consent",
+			location:   "https://consent.youtube.com/m?continue=https://www.youtube.com/watch",
+			expectedOk: true,
+		},
+		{
+			name:     "Consent page of another site",
+			location: "https://consent.example.com/ml?continue=https://www.google.com/maps/place/Hala%2BStulecia",
+		},
+		{
+			name:     "Lookalike Google domain",
+			location: "https://consent.google.com.example.com/ml?continue=https://www.google.com/maps",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: http.StatusFound,
+				Header:     http.Header{"Location": {tc.location}},
+				Request:    &http.Request{URL: &url.URL{Scheme: "https", Host: "maps.app.goo.gl", Path: "/abc"}},
+			}
+
+			_, ok := consentTarget(resp)
+			assert.Equal(t, tc.expectedOk, ok)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package maps
+
+import "math"
+
+// ellipsoid is the reference ellipsoid of a datum.
+type ellipsoid struct {
+	// a is the semi-major axis in metres.
+	a float64
+	// f is the flattening.
+	f float64
+}
+
+var (
+	wgs84      = ellipsoid{a: 6378137, f: 1 / 298.257223563}
+	airy1830   = ellipsoid{a: 6377563.396, f: 1 / 299.3249646}
+	bessel1841 = ellipsoid{a: 6377397.155, f: 1 / 299.1528128}
+)
+
+// eccentricity returns the first eccentricity of the ellipsoid.
+func (e ellipsoid) eccentricity() float64 {
+	return math.Sqrt(e.f * (2 - e.f))
+}
+
+// cartesian returns the earth-centred cartesian coordinates of the point on the surface of the ellipsoid.
+func (e ellipsoid) cartesian(lat, lng float64) (x, y, z float64) {
+	phi, lambda := lat*math.Pi/180, lng*math.Pi/180
+	e2 := e.f * (2 - e.f)
+	nu := e.a / math.Sqrt(1-e2*math.Sin(phi)*math.Sin(phi))
+	return nu * math.Cos(phi) * math.Cos(lambda), nu * math.Cos(phi) * math.Sin(lambda), (1 - e2) * nu * math.Sin(phi)
+}
+
+// geodetic reverses cartesian with Bowring's method, accurate to well below a millimetre on the surface.
+func (e ellipsoid) geodetic(x, y, z float64) (lat, lng float64) {
+	e2 := e.f * (2 - e.f)
+	b := e.a * (1 - e.f)
+	ep2 := e2 / (1 - e2)
+	p := math.Hypot(x, y)
+	beta := math.Atan2(z*e.a, p*b)
+	phi := math.Atan2(z+ep2*b*math.Pow(math.Sin(beta), 3), p-e2*e.a*math.Pow(math.Cos(beta), 3))
+	return phi * 180 / math.Pi, math.Atan2(y, x) * 180 / math.Pi
+}
+
+// helmert is a seven-parameter transformation between the cartesian coordinates of two datums,
+// with the rotations following the position vector convention.
+type helmert struct {
+	// tx, ty and tz are the translations in metres.
+	tx, ty, tz float64
+	// rx, ry and rz are the rotations in arc seconds.
+	rx, ry, rz float64
+	// s is the scale change in parts per million.
+	s float64
+}
+
+func (h helmert) apply(x, y, z float64) (float64, float64, float64) {
+	const arcSecond = math.Pi / (180 * 3600)
+	rx, ry, rz := h.rx*arcSecond, h.ry*arcSecond, h.rz*arcSecond
+	scale := 1 + h.s*1e-6
+	return h.tx + scale*x - rz*y + ry*z,
+		h.ty + rz*x + scale*y - rx*z,
+		h.tz - ry*x + rx*y + scale*z
+}
+
+// inverse returns the reverse transformation, which is accurate to a millimetre for the small rotations of datum shifts.
+func (h helmert) inverse() helmert {
+	return helmert{tx: -h.tx, ty: -h.ty, tz: -h.tz, rx: -h.rx, ry: -h.ry, rz: -h.rz, s: -h.s}
+}
+
+// datum is a reference ellipsoid along with the transformation of its coordinates into WGS84.
+type datum struct {
+	ellipsoid ellipsoid
+	toWGS84   helmert
+}
+
+var (
+	wgs84Datum = datum{ellipsoid: wgs84}
+	// osgb36Datum is the datum of the British National Grid, the transformation being accurate to about 5 metres.
+	osgb36Datum = datum{
+		ellipsoid: airy1830,
+		toWGS84:   helmert{tx: -446.448, ty: 125.157, tz: -542.060, rx: -0.1502, ry: -0.2470, rz: -0.8421, s: 20.4894}.inverse(),
+	}
+	// ch1903PlusDatum is the datum of the Swiss LV95 grid, the transformation being accurate to about a metre.
+	ch1903PlusDatum = datum{ellipsoid: bessel1841, toWGS84: helmert{tx: 674.374, ty: 15.056, tz: 405.346}}
+)
+
+// toWGS84LatLng transforms the coordinates of the datum into WGS84 ones.
+func (d datum) toWGS84LatLng(lat, lng float64) LatLng {
+	if d == wgs84Datum {
+		return LatLng{Latitude: lat, Longitude: lng}
+	}
+	lat, lng = wgs84.geodetic(d.toWGS84.apply(d.ellipsoid.cartesian(lat, lng)))
+	return LatLng{Latitude: lat, Longitude: lng}
+}
+
+// fromWGS84LatLng transforms WGS84 coordinates into the ones of the datum.
+func (d datum) fromWGS84LatLng(latLng LatLng) (lat, lng float64) {
+	if d == wgs84Datum {
+		return latLng.Latitude, latLng.Longitude
+	}
+	return d.ellipsoid.geodetic(d.toWGS84.inverse().apply(wgs84.cartesian(latLng.Latitude, latLng.Longitude)))
+}
+
+// projection maps the geodetic coordinates of its ellipsoid onto a grid and back.
+type projection interface {
+	forward(lat, lng float64) (easting, northing float64)
+	inverse(easting, northing float64) (lat, lng float64)
+}
+
+// crs is a coordinate reference system of grid coordinates projected from a datum.
+type crs struct {
+	datum      datum
+	projection projection
+}
+
+// latLng returns the WGS84 location of the grid coordinates.
+func (c crs) latLng(easting, northing float64) LatLng {
+	return c.datum.toWGS84LatLng(c.projection.inverse(easting, northing))
+}
+
+// grid returns the grid coordinates of the WGS84 location.
+func (c crs) grid(latLng LatLng) (easting, northing float64) {
+	return c.projection.forward(c.datum.fromWGS84LatLng(latLng))
+}
+
+// transverseMercator is the projection of UTM and of most national grids, computed with the Krüger series,
+// accurate to a millimetre within thousands of kilometres from the central meridian.
+type transverseMercator struct {
+	ellipsoid ellipsoid
+	scale     float64
+	// originLat and originLng are the true origin of the grid, originLng being the central meridian.
+	originLat, originLng        float64
+	falseEasting, falseNorthing float64
+}
+
+func (p transverseMercator) forward(lat, lng float64) (easting, northing float64) {
+	x, y := p.ellipsoid.krugerForward(lat, lng-p.originLng)
+	_, y0 := p.ellipsoid.krugerForward(p.originLat, 0)
+	return p.falseEasting + p.scale*x, p.falseNorthing + p.scale*(y-y0)
+}
+
+func (p transverseMercator) inverse(easting, northing float64) (lat, lng float64) {
+	_, y0 := p.ellipsoid.krugerForward(p.originLat, 0)
+	lat, lng = p.ellipsoid.krugerInverse((easting-p.falseEasting)/p.scale, (northing-p.falseNorthing)/p.scale+y0)
+	return lat, normalizeLongitude(lng + p.originLng)
+}
+
+// krugerForward projects the latitude and the longitude relative to the central meridian,
+// returning the coordinates unscaled and relative to the equator.
+func (e ellipsoid) krugerForward(lat, lng float64) (x, y float64) {
+	n, A := e.krugerParameters()
+	alpha := [3]float64{
+		n/2 - 2*n*n/3 + 5*n*n*n/16,
+		13*n*n/48 - 3*n*n*n/5,
+		61 * n * n * n / 240,
+	}
+	ecc := e.eccentricity()
+	phi, lambda := lat*math.Pi/180, lng*math.Pi/180
+	t := math.Sinh(math.Atanh(math.Sin(phi)) - ecc*math.Atanh(ecc*math.Sin(phi)))
+	xi := math.Atan2(t, math.Cos(lambda))
+	eta := math.Atanh(math.Sin(lambda) / math.Sqrt(1+t*t))
+	x, y = eta, xi
+	for j, a := range alpha {
+		k := 2 * float64(j+1)
+		x += a * math.Cos(k*xi) * math.Sinh(k*eta)
+		y += a * math.Sin(k*xi) * math.Cosh(k*eta)
+	}
+	return A * x, A * y
+}
+
+// krugerInverse reverses krugerForward.
+func (e ellipsoid) krugerInverse(x, y float64) (lat, lng float64) {
+	n, A := e.krugerParameters()
+	beta := [3]float64{
+		n/2 - 2*n*n/3 + 37*n*n*n/96,
+		n*n/48 + n*n*n/15,
+		17 * n * n * n / 480,
+	}
+	delta := [3]float64{
+		2*n - 2*n*n/3 - 2*n*n*n,
+		7*n*n/3 - 8*n*n*n/5,
+		56 * n * n * n / 15,
+	}
+	xi, eta := y/A, x/A
+	xiPrime, etaPrime := xi, eta
+	for j, b := range beta {
+		k := 2 * float64(j+1)
+		xiPrime -= b * math.Sin(k*xi) * math.Cosh(k*eta)
+		etaPrime -= b * math.Cos(k*xi) * math.Sinh(k*eta)
+	}
+	chi := math.Asin(math.Sin(xiPrime) / math.Cosh(etaPrime))
+	phi := chi
+	for j, d := range delta {
+		phi += d * math.Sin(2*float64(j+1)*chi)
+	}
+	lambda := math.Atan2(math.Sinh(etaPrime), math.Cos(xiPrime))
+	return phi * 180 / math.Pi, lambda * 180 / math.Pi
+}
+
+// krugerParameters returns the third flattening and the rectifying radius of the ellipsoid.
+func (e ellipsoid) krugerParameters() (n, A float64) {
+	n = e.f / (2 - e.f)
+	A = e.a / (1 + n) * (1 + n*n/4 + n*n*n*n/64)
+	return n, A
+}
+
+// swissObliqueMercator is the conformal cylindrical projection of the Swiss grids, projecting the ellipsoid
+// onto a sphere first and then onto a cylinder touching the sphere along the great circle through Bern.
+type swissObliqueMercator struct {
+	ellipsoid ellipsoid
+	// originLat and originLng are the old observatory of Bern.
+	originLat, originLng        float64
+	falseEasting, falseNorthing float64
+}
+
+// sphere returns the radius of the projection sphere, the ratio of its longitudes to the ellipsoid ones,
+// the latitude of the origin on the sphere and the constant of the latitude mapping.
+func (p swissObliqueMercator) sphere() (radius, alpha, b0, k float64) {
+	e := p.ellipsoid.eccentricity()
+	e2 := e * e
+	phi0 := p.originLat * math.Pi / 180
+	sin0 := math.Sin(phi0)
+	radius = p.ellipsoid.a * math.Sqrt(1-e2) / (1 - e2*sin0*sin0)
+	alpha = math.Sqrt(1 + e2/(1-e2)*math.Pow(math.Cos(phi0), 4))
+	b0 = math.Asin(sin0 / alpha)
+	k = math.Log(math.Tan(math.Pi/4+b0/2)) - alpha*math.Log(math.Tan(math.Pi/4+phi0/2)) +
+		alpha*e/2*math.Log((1+e*sin0)/(1-e*sin0))
+	return radius, alpha, b0, k
+}
+
+func (p swissObliqueMercator) forward(lat, lng float64) (easting, northing float64) {
+	radius, alpha, b0, k := p.sphere()
+	e := p.ellipsoid.eccentricity()
+	phi := lat * math.Pi / 180
+	s := alpha*math.Log(math.Tan(math.Pi/4+phi/2)) - alpha*e/2*math.Log((1+e*math.Sin(phi))/(1-e*math.Sin(phi))) + k
+	b := 2 * (math.Atan(math.Exp(s)) - math.Pi/4)
+	l := alpha * (lng - p.originLng) * math.Pi / 180
+	// Rotate the sphere so that the origin lands on the equator.
+	lBar := math.Atan(math.Sin(l) / (math.Sin(b0)*math.Tan(b) + math.Cos(b0)*math.Cos(l)))
+	bBar := math.Asin(math.Cos(b0)*math.Sin(b) - math.Sin(b0)*math.Cos(b)*math.Cos(l))
+	return p.falseEasting + radius*lBar, p.falseNorthing + radius/2*math.Log((1+math.Sin(bBar))/(1-math.Sin(bBar)))
+}
+
+func (p swissObliqueMercator) inverse(easting, northing float64) (lat, lng float64) {
+	radius, alpha, b0, k := p.sphere()
+	e := p.ellipsoid.eccentricity()
+	lBar := (easting - p.falseEasting) / radius
+	bBar := 2 * (math.Atan(math.Exp((northing-p.falseNorthing)/radius)) - math.Pi/4)
+	b := math.Asin(math.Cos(b0)*math.Sin(bBar) + math.Sin(b0)*math.Cos(bBar)*math.Cos(lBar))
+	l := math.Atan(math.Sin(lBar) / (math.Cos(b0)*math.Cos(lBar) - math.Sin(b0)*math.Tan(bBar)))
+	// The latitude on the ellipsoid is found iteratively, converging to well below a millimetre in a few steps.
+	phi := b
+	for i := 0; i < 10; i++ {
+		s := (math.Log(math.Tan(math.Pi/4+b/2))-k)/alpha + e*math.Log(math.Tan(math.Pi/4+math.Asin(e*math.Sin(phi))/2))
+		phi = 2*math.Atan(math.Exp(s)) - math.Pi/2
+	}
+	return phi * 180 / math.Pi, p.originLng + l/alpha*180/math.Pi
+}
@@ -0,0 +1,80 @@
+package maps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func dms(degrees, minutes, seconds float64) float64 {
+	return degrees + minutes/60 + seconds/3600
+}
+
+func TestTransverseMercator_BritishNationalGrid(t *testing.T) {
+	// The worked example of the Ordnance Survey guide to coordinate systems in Great Britain, on OSGB36.
+	lat, lng := dms(52, 39, 27.2531), dms(1, 43, 4.5177)
+	p := britishNationalGrid.projection
+
+	easting, northing := p.forward(lat, lng)
+
+	assert.InDelta(t, 651409.903, easting, 1e-3)
+	assert.InDelta(t, 313177.270, northing, 1e-3)
+	lat2, lng2 := p.inverse(easting, northing)
+	assert.InDelta(t, lat, lat2, 1e-8)
+	assert.InDelta(t, lng, lng2, 1e-8)
+}
+
+func TestSwissObliqueMercator(t *testing.T) {
+	p := swissGrid.projection
+
+	easting, northing := p.forward(swissGrid.projection.(swissObliqueMercator).originLat, swissGrid.projection.(swissObliqueMercator).originLng)
+
+	assert.InDelta(t, 2600000, easting, 1e-3)
+	assert.InDelta(t, 1200000, northing, 1e-3)
+	lat, lng := p.inverse(2700000, 1100000)
+	easting, northing = p.forward(lat, lng)
+	assert.InDelta(t, 2700000, easting, 1e-3)
+	assert.InDelta(t, 1100000, northing, 1e-3)
+}
+
+func TestDatum(t *testing.T) {
+	testCases := []struct {
+		name     string
+		datum    datum
+		lat, lng float64
+		// expectedLatLng is the published WGS84 location of the point.
+		expectedLatLng LatLng
+		// expectedDelta is about the accuracy of the transformation of the datum.
+		expectedDelta float64
+	}{
+		{
+			name:           "OSGB36 Caister water tower",
+			datum:          osgb36Datum,
+			lat:            dms(52, 39, 27.2531),
+			lng:            dms(1, 43, 4.5177),
+			expectedLatLng: LatLng{Latitude: dms(52, 39, 28.72), Longitude: dms(1, 42, 57.79)},
+			expectedDelta:  5e-5,
+		},
+		{
+			name:           "WGS84",
+			datum:          wgs84Datum,
+			lat:            51.1069402,
+			lng:            17.0772095,
+			expectedLatLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095},
+			expectedDelta:  1e-12,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			latLng := tc.datum.toWGS84LatLng(tc.lat, tc.lng)
+
+			assert.InDelta(t, tc.expectedLatLng.Latitude, latLng.Latitude, tc.expectedDelta)
+			assert.InDelta(t, tc.expectedLatLng.Longitude, latLng.Longitude, tc.expectedDelta)
+			// Reversing the transformation with the negated parameters is accurate to a few millimetres.
+			lat, lng := tc.datum.fromWGS84LatLng(latLng)
+			assert.InDelta(t, tc.lat, lat, 1e-7)
+			assert.InDelta(t, tc.lng, lng, 1e-7)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package maps
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DataParam is a single `!<index><type><value>` entry of the Google Maps `data=` parameter.
+// Message entries (type 'm') carry the number of nested entries as their value and hold them in Children.
+type DataParam struct {
+	Index    int
+	Type     byte
+	Value    string
+	Children []*DataParam
+}
+
+const dataMessageType = 'm'
+
+// Child returns the first direct child with the given index and type, or nil when there is none.
+func (p *DataParam) Child(index int, typ byte) *DataParam {
+	for _, c := range p.Children {
+		if c.Index == index && c.Type == typ {
+			return c
+		}
+	}
+	return nil
+}
+
+// Float parses the value of a numeric entry.
+func (p *DataParam) Float() (float64, error) {
+	return strconv.ParseFloat(p.Value, 64)
+}
+
+// Data is a decoded Google Maps `data=` parameter.
+type Data struct {
+	Params []*DataParam
+}
+
+// ParseData decodes the `!<index><type><value>` mini-format used by Google Maps `data=` parameters into a tree.
+func ParseData(raw string) (*Data, error) {
+	tokens := strings.Split(strings.ReplaceAll(raw, "%21", "!"), "!")
+	var params []*DataParam
+	for _, token := range tokens {
+		if token == "" {
+			continue
+		}
+		p, err := parseDataToken(token)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, p)
+	}
+	root, rest, err := nestDataParams(params, len(params))
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("failed to nest %d trailing data params", len(rest))
+	}
+	return &Data{Params: root}, nil
+}
+
+func parseDataToken(token string) (*DataParam, error) {
+	i := 0
+	for i < len(token) && token[i] >= '0' && token[i] <= '9' {
+		i++
+	}
+	if i == 0 || i == len(token) {
+		return nil, fmt.Errorf("failed to parse data param: %s", token)
+	}
+	index, err := strconv.Atoi(token[:i])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse data param index: %w", err)
+	}
+	p := &DataParam{Index: index, Type: token[i], Value: token[i+1:]}
+	if p.Type == 's' {
+		p.Value = unescapeDataString(p.Value)
+	}
+	return p, nil
+}
+
+// nestDataParams consumes count params from the flat list, attaching the descendants of every message to it.
+func nestDataParams(params []*DataParam, count int) ([]*DataParam, []*DataParam, error) {
+	var nested []*DataParam
+	for count > 0 {
+		if len(params) == 0 {
+			return nil, nil, fmt.Errorf("failed to nest data params: %d missing", count)
+		}
+		p := params[0]
+		params = params[1:]
+		count--
+		if p.Type != dataMessageType {
+			nested = append(nested, p)
+			continue
+		}
+		size, err := strconv.Atoi(p.Value)
+		if err != nil || size < 0 || size > count {
+			return nil, nil, fmt.Errorf("failed to parse data message size: %s", p.Value)
+		}
+		p.Children, params, err = nestDataParams(params, size)
+		if err != nil {
+			return nil, nil, err
+		}
+		count -= size
+		nested = append(nested, p)
+	}
+	return nested, params, nil
+}
+
+// unescapeDataString reverts the `*21` (!) and `*2A` (*) escaping of string values along with any URL escaping.
+func unescapeDataString(s string) string {
+	s = strings.NewReplacer("*21", "!", "*2A", "*", "*2a", "*").Replace(s)
+	if unescaped, err := url.PathUnescape(s); err == nil {
+		return unescaped
+	}
+	return s
+}
+
+// Pin returns the place pin stored as a `!3d<lat>!4d<lng>` pair.
+func (d *Data) Pin() (LatLng, bool) {
+	var pin LatLng
+	found := false
+	walkDataParams(d.Params, func(p *DataParam) bool {
+		if latLng, ok := dataLatLng(p, 3, 4); ok {
+			pin, found = latLng, true
+			return false
+		}
+		return true
+	})
+	return pin, found
+}
+
+// Stops returns the directions stops stored as `!1d<lng>!2d<lat>` pairs, in order.
+func (d *Data) Stops() []LatLng {
+	var stops []LatLng
+	walkDataParams(d.Params, func(p *DataParam) bool {
+		if lngLat, ok := dataLatLng(p, 1, 2); ok {
+			stops = append(stops, LatLng{Latitude: lngLat.Longitude, Longitude: lngLat.Latitude})
+		}
+		return true
+	})
+	return stops
+}
+
+// ListID returns the identifier of the saved list stored as a `!11m2!2s<id>` message.
+func (d *Data) ListID() (string, bool) {
+	id := ""
+	walkDataParams(d.Params, func(p *DataParam) bool {
+		if p.Index != 11 || p.Type != dataMessageType {
+			return true
+		}
+		if c := p.Child(2, 's'); c != nil && c.Value != "" {
+			id = c.Value
+			return false
+		}
+		return true
+	})
+	return id, id != ""
+}
+
+// dataLatLng reads the pair of doubles with the given indexes from the direct children of a message.
+func dataLatLng(p *DataParam, latIndex, lngIndex int) (LatLng, bool) {
+	if p.Type != dataMessageType {
+		return LatLng{}, false
+	}
+	latParam, lngParam := p.Child(latIndex, 'd'), p.Child(lngIndex, 'd')
+	if latParam == nil || lngParam == nil {
+		return LatLng{}, false
+	}
+	lat, err := latParam.Float()
+	if err != nil {
+		return LatLng{}, false
+	}
+	lng, err := lngParam.Float()
+	if err != nil {
+		return LatLng{}, false
+	}
+	return LatLng{Latitude: lat, Longitude: lng}, true
+}
+
+// walkDataParams visits params depth-first until f returns false.
+func walkDataParams(params []*DataParam, f func(p *DataParam) bool) bool {
+	for _, p := range params {
+		if !f(p) || !walkDataParams(p.Children, f) {
+			return false
+		}
+	}
+	return true
+}
+
+// dataFromURL decodes the `data=` parameter found either as a path segment or as a query parameter.
+func dataFromURL(u *url.URL) (*Data, bool) {
+	raw := ""
+	for _, segment := range strings.Split(u.EscapedPath(), "/") {
+		if strings.HasPrefix(segment, "data=") {
+			raw = strings.TrimPrefix(segment, "data=")
+			break
+		}
+	}
+	if raw == "" {
+		raw = u.Query().Get("data")
+	}
+	if raw == "" {
+		return nil, false
+	}
+	data, err := ParseData(raw)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
@@ -0,0 +1,79 @@
+package maps
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const nirvanaData = "!4m20!1m13!4m12!1m4!2m2!1d115.1565824!2d-8.6409216!4e1!1m6!1m2!1s0x2dd239c88caf5ab7:0xc82282485f1666e1!2sNirvana+Life+Indonesia!2m2!1d115.1646432!2d-8.6455071!3m5!1s0x2dd239c88caf5ab7:0xc82282485f1666e1!8m2!3d-8.6455071!4d115.1646432!16s%2Fg%2F11rq1h0c40"
+
+func TestParseData(t *testing.T) {
+	data, err := ParseData(nirvanaData)
+	require.NoError(t, err)
+
+	require.Len(t, data.Params, 1)
+	root := data.Params[0]
+	assert.Equal(t, 4, root.Index)
+	assert.Equal(t, byte('m'), root.Type)
+	require.Len(t, root.Children, 2)
+
+	place := root.Child(3, 'm')
+	require.NotNil(t, place)
+	require.Len(t, place.Children, 3)
+	assert.Equal(t, "0x2dd239c88caf5ab7:0xc82282485f1666e1", place.Child(1, 's').Value)
+	assert.Equal(t, "/g/11rq1h0c40", place.Child(16, 's').Value)
+
+	pin, ok := data.Pin()
+	require.True(t, ok)
+	assert.Equal(t, LatLng{Latitude: -8.6455071, Longitude: 115.1646432}, pin)
+
+	assert.Equal(t, []LatLng{
+		{Latitude: -8.6409216, Longitude: 115.1565824},
+		{Latitude: -8.6455071, Longitude: 115.1646432},
+	}, data.Stops())
+}
+
+func TestParseData_Invalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		raw  string
+	}{
+		{name: "Missing type", raw: "!4"},
+		{name: "Missing index", raw: "!m2"},
+		{name: "Truncated message", raw: "!3m5!1sfoo"},
+		{name: "Invalid message size", raw: "!3mfoo"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseData(tc.raw)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseData_EscapedString(t *testing.T) {
+	data, err := ParseData("!1sHello*21+World*2A")
+	require.NoError(t, err)
+	require.Len(t, data.Params, 1)
+	assert.Equal(t, "Hello!+World*", data.Params[0].Value)
+}
+
+func TestDataFromURL(t *testing.T) {
+	u, err := url.Parse("https://www.google.com/maps/place/Nirvana/@-8.643427,115.1495802,15z/data=" + nirvanaData + "?entry=ttu")
+	require.NoError(t, err)
+
+	data, ok := dataFromURL(u)
+	require.True(t, ok)
+	pin, ok := data.Pin()
+	require.True(t, ok)
+	assert.Equal(t, LatLng{Latitude: -8.6455071, Longitude: 115.1646432}, pin)
+
+	u, err = url.Parse("https://www.google.com/maps/place/Nirvana/@-8.643427,115.1495802,15z")
+	require.NoError(t, err)
+	_, ok = dataFromURL(u)
+	assert.False(t, ok)
+}
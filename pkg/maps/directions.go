@@ -0,0 +1,165 @@
+package maps
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// RouteStop is a single stop of a directions link given either as coordinates or as a free-text query.
+type RouteStop struct {
+	Query  string
+	LatLng *LatLng
+}
+
+// Route is an ordered list of stops, from the origin through the waypoints to the destination.
+type Route struct {
+	Stops []RouteStop
+}
+
+// Origin returns the first stop of the route.
+func (r *Route) Origin() (RouteStop, bool) {
+	if len(r.Stops) == 0 {
+		return RouteStop{}, false
+	}
+	return r.Stops[0], true
+}
+
+// Destination returns the final stop of the route.
+func (r *Route) Destination() (RouteStop, bool) {
+	if len(r.Stops) == 0 {
+		return RouteStop{}, false
+	}
+	return r.Stops[len(r.Stops)-1], true
+}
+
+// Waypoints returns the stops between the origin and the destination.
+func (r *Route) Waypoints() []RouteStop {
+	if len(r.Stops) < 3 {
+		return nil
+	}
+	return r.Stops[1 : len(r.Stops)-1]
+}
+
+const (
+	routeStopLatLngRegex = `^\s*(-?\d+(?:\.\d+)?)\s*,\s*(-?\d+(?:\.\d+)?)\s*$`
+	directionsPathPrefix = "/maps/dir/"
+	daddrSeparator       = " to:"
+	waypointsSeparator   = "|"
+)
+
+var routeStopLatLngPattern = regexp.MustCompile(routeStopLatLngRegex)
+
+// ParseRoute extracts the directions route of a Google Maps link.
+// Stops are read from the `api=1` origin/waypoints/destination parameters, the legacy saddr/daddr parameters,
+// or the slash-separated segments of a `/maps/dir/` path, in that order of preference.
+// Stops given by name are resolved from the `!1d<lng>!2d<lat>` entries of the data parameter when possible.
+func ParseRoute(u *url.URL) (*Route, bool) {
+	stops := apiRouteStops(u.Query())
+	if len(stops) == 0 {
+		stops = legacyRouteStops(u.Query())
+	}
+	if len(stops) == 0 {
+		stops = pathRouteStops(u)
+	}
+	if len(stops) == 0 {
+		return nil, false
+	}
+	if data, ok := dataFromURL(u); ok {
+		resolveRouteStops(stops, data.Stops())
+	}
+	return &Route{Stops: stops}, true
+}
+
+func apiRouteStops(q url.Values) []RouteStop {
+	if q.Get("api") != "1" || q.Get("destination") == "" {
+		return nil
+	}
+	var stops []RouteStop
+	if origin := q.Get("origin"); origin != "" {
+		stops = append(stops, newRouteStop(origin))
+	}
+	if waypoints := q.Get("waypoints"); waypoints != "" {
+		for _, w := range strings.Split(waypoints, waypointsSeparator) {
+			if strings.TrimSpace(w) != "" {
+				stops = append(stops, newRouteStop(w))
+			}
+		}
+	}
+	return append(stops, newRouteStop(q.Get("destination")))
+}
+
+func legacyRouteStops(q url.Values) []RouteStop {
+	daddr := q.Get("daddr")
+	if daddr == "" {
+		return nil
+	}
+	var stops []RouteStop
+	if saddr := q.Get("saddr"); saddr != "" {
+		stops = append(stops, newRouteStop(saddr))
+	}
+	for _, d := range strings.Split(daddr, daddrSeparator) {
+		if strings.TrimSpace(d) != "" {
+			stops = append(stops, newRouteStop(d))
+		}
+	}
+	return stops
+}
+
+func pathRouteStops(u *url.URL) []RouteStop {
+	escaped := u.EscapedPath()
+	if !strings.HasPrefix(escaped, directionsPathPrefix) {
+		return nil
+	}
+	var stops []RouteStop
+	for _, segment := range strings.Split(strings.TrimPrefix(escaped, directionsPathPrefix), "/") {
+		if strings.HasPrefix(segment, "@") || strings.HasPrefix(segment, "data=") {
+			break
+		}
+		// Empty segments stand for the current location of the user.
+		if segment == "" {
+			continue
+		}
+		query, err := url.PathUnescape(strings.ReplaceAll(segment, "+", " "))
+		if err != nil {
+			continue
+		}
+		stops = append(stops, newRouteStop(query))
+	}
+	return stops
+}
+
+func newRouteStop(query string) RouteStop {
+	query = strings.TrimSpace(query)
+	stop := RouteStop{Query: query}
+	// Coordinates out of range leave the stop unresolved, like a stop given by name.
+	if latLng, ok := strictLatLng(query, routeStopLatLngPattern); ok {
+		stop.LatLng = &latLng
+	}
+	return stop
+}
+
+// resolveRouteStops assigns coordinates from the data parameter to stops given by name.
+// Data stops are matched one to one, either against all stops or against the unresolved ones only.
+func resolveRouteStops(stops []RouteStop, dataStops []LatLng) {
+	var unresolved []int
+	for i := range stops {
+		if stops[i].LatLng == nil {
+			unresolved = append(unresolved, i)
+		}
+	}
+	switch len(dataStops) {
+	case 0:
+		return
+	case len(stops):
+		for _, i := range unresolved {
+			latLng := dataStops[i]
+			stops[i].LatLng = &latLng
+		}
+	case len(unresolved):
+		for j, i := range unresolved {
+			latLng := dataStops[j]
+			stops[i].LatLng = &latLng
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package maps
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func latLngPtr(lat, lng float64) *LatLng {
+	return &LatLng{Latitude: lat, Longitude: lng}
+}
+
+func TestParseRoute(t *testing.T) {
+	testCases := []struct {
+		name          string
+		inputURL      string
+		expectedStops []RouteStop
+	}{
+		{
+			name:     "API destination only",
+			inputURL: "https://www.google.com/maps/dir/?api=1&destination=51.107885,17.038538",
+			expectedStops: []RouteStop{
+				{Query: "51.107885,17.038538", LatLng: latLngPtr(51.107885, 17.038538)},
+			},
+		},
+		{
+			name:     "API origin, waypoints and destination",
+			inputURL: "https://www.google.com/maps/dir/?api=1&origin=52.2297,21.0122&waypoints=Lodz|51.7592,19.4560&destination=51.107885,17.038538",
+			expectedStops: []RouteStop{
+				{Query: "52.2297,21.0122", LatLng: latLngPtr(52.2297, 21.0122)},
+				{Query: "Lodz"},
+				{Query: "51.7592,19.4560", LatLng: latLngPtr(51.7592, 19.4560)},
+				{Query: "51.107885,17.038538", LatLng: latLngPtr(51.107885, 17.038538)},
+			},
+		},
+		{
+			name:     "Legacy saddr and daddr with multiple destinations",
+			inputURL: "https://maps.google.com/maps?saddr=52.2297,21.0122&daddr=Lodz+to:51.107885,17.038538",
+			expectedStops: []RouteStop{
+				{Query: "52.2297,21.0122", LatLng: latLngPtr(52.2297, 21.0122)},
+				{Query: "Lodz"},
+				{Query: "51.107885,17.038538", LatLng: latLngPtr(51.107885, 17.038538)},
+			},
+		},
+		{
+			name:     "Dir path with coordinates",
+			inputURL: "https://www.google.com/maps/dir/52.2297,21.0122/51.107885,17.038538/@51.6,19.0,7z",
+			expectedStops: []RouteStop{
+				{Query: "52.2297,21.0122", LatLng: latLngPtr(52.2297, 21.0122)},
+				{Query: "51.107885,17.038538", LatLng: latLngPtr(51.107885, 17.038538)},
+			},
+		},
+		{
+			name:     "Dir path with named stops resolved from data",
+			inputURL: "https://www.google.com/maps/dir/Warsaw/Wroc%C5%82aw+Rynek/@51.6,19.0,7z/data=!4m13!4m12!1m5!1m1!1s0x0:0x1!2m2!1d21.0122!2d52.2297!1m5!1m1!1s0x0:0x2!2m2!1d17.0320!2d51.1098",
+			expectedStops: []RouteStop{
+				{Query: "Warsaw", LatLng: latLngPtr(52.2297, 21.0122)},
+				{Query: "Wrocław Rynek", LatLng: latLngPtr(51.1098, 17.0320)},
+			},
+		},
+		{
+			name:     "Dir path with out of range coordinates",
+			inputURL: "https://www.google.com/maps/dir/100,200/51.107885,17.038538",
+			expectedStops: []RouteStop{
+				{Query: "100,200"},
+				{Query: "51.107885,17.038538", LatLng: latLngPtr(51.107885, 17.038538)},
+			},
+		},
+		{
+			name:     "Dir path from current location",
+			inputURL: "https://www.google.com/maps/dir//51.107885,17.038538",
+			expectedStops: []RouteStop{
+				{Query: "51.107885,17.038538", LatLng: latLngPtr(51.107885, 17.038538)},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			route, ok := ParseRoute(u)
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedStops, route.Stops)
+		})
+	}
+}
+
+func TestParseRoute_NotDirections(t *testing.T) {
+	u, err := url.Parse("https://www.google.com/maps/place/37.4219999,122.0840575")
+	require.NoError(t, err)
+
+	_, ok := ParseRoute(u)
+	assert.False(t, ok)
+}
+
+func TestRoute_Stops(t *testing.T) {
+	route := &Route{Stops: []RouteStop{{Query: "A"}, {Query: "B"}, {Query: "C"}, {Query: "D"}}}
+
+	origin, ok := route.Origin()
+	require.True(t, ok)
+	assert.Equal(t, "A", origin.Query)
+
+	destination, ok := route.Destination()
+	require.True(t, ok)
+	assert.Equal(t, "D", destination.Query)
+
+	assert.Equal(t, []RouteStop{{Query: "B"}, {Query: "C"}}, route.Waypoints())
+
+	_, ok = (&Route{}).Destination()
+	assert.False(t, ok)
+}
+
+func TestParseGoogleMapsFromURL_Directions(t *testing.T) {
+	u, err := url.Parse("https://www.google.com/maps/dir/52.2297,21.0122/51.107885,17.038538/@51.6,19.0,7z")
+	require.NoError(t, err)
+
+	link, err := ParseGoogleMapsFromURL(context.Background(), u, func(context.Context, *url.URL) (string, error) {
+		t.Fatal("unexpected content fetch")
+		return "", nil
+	})
+	require.NoError(t, err)
+
+	latLng, err := link.LatLng()
+	require.NoError(t, err)
+	assert.Equal(t, LatLng{Latitude: 51.107885, Longitude: 17.038538}, latLng)
+	require.NotNil(t, link.Route())
+	assert.Len(t, link.Route().Stops, 2)
+}
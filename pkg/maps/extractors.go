@@ -0,0 +1,202 @@
+package maps
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// urlExtractor extracts a Google Maps link from the URL alone, without fetching its content.
+type urlExtractor func(u *url.URL) (*GoogleMapsLink, bool)
+
+// urlExtractors all run, each contributing the candidate of the form it knows.
+// The name and the route are taken from the first extractor carrying them, so more specific forms come first.
+// The pin of a `q=` or `query=` search outranks the `ll=` and `center=` viewport by confidence.
+var urlExtractors = []urlExtractor{
+	extractDataPin,
+	extractRouteDestination,
+	extractQueryParam("q", confidenceQuery),
+	extractQueryParam("query", confidenceQuery),
+	extractQueryParam("ll", confidenceViewport),
+	extractQueryParam("center", confidenceViewport),
+	extractQueryParam("sll", confidenceViewport),
+	extractSearchPath,
+	extractAtPath,
+	extractPath,
+}
+
+const (
+	// queryLatLngRegex matches `lat,lng` optionally prefixed with `loc:` and followed by a `(label)`.
+	queryLatLngRegex  = `^(?:loc:)?\s*(-?\d+(?:\.\d+)?)\s*,\s*(-?\d+(?:\.\d+)?)\s*(?:\((.*)\))?\s*$`
+	atPathLatLngRegex = `^@(-?\d+(?:\.\d+)?),(-?\d+(?:\.\d+)?)`
+	searchPathPrefix  = "/maps/search/"
+	atPathPrefix      = "/maps/@"
+)
+
+var (
+	queryLatLngPattern  = regexp.MustCompile(queryLatLngRegex)
+	atPathLatLngPattern = regexp.MustCompile(atPathLatLngRegex)
+)
+
+// googleMapsFromURL runs all URL extractors, ranking the candidates they found by confidence.
+// Street View and Google Earth links point at their camera instead, whatever place the rest of the link names.
+func googleMapsFromURL(u *url.URL) (*GoogleMapsLink, bool) {
+	if link, ok := extractCamera(u); ok {
+		return link, true
+	}
+	var links []*GoogleMapsLink
+	for _, extract := range urlExtractors {
+		if link, ok := extract(u); ok {
+			links = append(links, link)
+		}
+	}
+	return mergeLinks(links)
+}
+
+func extractDataPin(u *url.URL) (*GoogleMapsLink, bool) {
+	data, ok := dataFromURL(u)
+	if !ok {
+		return nil, false
+	}
+	pin, ok := data.Pin()
+	if !ok {
+		return nil, false
+	}
+	return newCandidateLink(newCandidate(pin, SourceData, confidencePin, false)), true
+}
+
+func extractRouteDestination(u *url.URL) (*GoogleMapsLink, bool) {
+	route, ok := ParseRoute(u)
+	if !ok {
+		return nil, false
+	}
+	destination, ok := route.Destination()
+	if !ok || destination.LatLng == nil {
+		return nil, false
+	}
+	link := newCandidateLink(newCandidate(*destination.LatLng, routeStopSource(u, destination), confidenceRoute, false))
+	link.route = route
+	return link, true
+}
+
+// routeStopSource tells whether the coordinates of the stop were given in the link or resolved from its data parameter.
+func routeStopSource(u *url.URL, stop RouteStop) Source {
+	if !routeStopLatLngPattern.MatchString(stop.Query) {
+		return SourceData
+	}
+	if strings.HasPrefix(u.EscapedPath(), directionsPathPrefix) && u.Query().Get("destination") == "" && u.Query().Get("daddr") == "" {
+		return SourcePath
+	}
+	return SourceQuery
+}
+
+// extractQueryParam reads `lat,lng` from the given query parameter, trusting it with the given confidence.
+func extractQueryParam(name string, confidence float64) urlExtractor {
+	return func(u *url.URL) (*GoogleMapsLink, bool) {
+		v := u.Query().Get(name)
+		latLng, ok := strictLatLng(v, queryLatLngPattern)
+		if !ok {
+			return nil, false
+		}
+		label := queryLatLngPattern.FindStringSubmatch(v)[3]
+		link := newCandidateLink(newCandidate(latLng, SourceQuery, confidence, false))
+		link.name = strings.TrimSpace(label)
+		return link, true
+	}
+}
+
+// extractSearchPath reads `lat,lng` from the segment following `/maps/search/`.
+func extractSearchPath(u *url.URL) (*GoogleMapsLink, bool) {
+	if !strings.HasPrefix(u.Path, searchPathPrefix) {
+		return nil, false
+	}
+	segment := strings.SplitN(strings.TrimPrefix(u.Path, searchPathPrefix), "/", 2)[0]
+	latLng, ok := strictLatLng(strings.ReplaceAll(segment, "+", " "), queryLatLngPattern)
+	if !ok {
+		return nil, false
+	}
+	return newCandidateLink(newCandidate(latLng, SourcePath, confidenceSearch, false)), true
+}
+
+// extractAtPath reads `lat,lng` from a bare `/maps/@lat,lng,zoom` map view.
+func extractAtPath(u *url.URL) (*GoogleMapsLink, bool) {
+	if !strings.HasPrefix(u.Path, atPathPrefix) {
+		return nil, false
+	}
+	latLng, ok := strictLatLng(strings.TrimPrefix(u.Path, "/maps/"), atPathLatLngPattern)
+	if !ok {
+		return nil, false
+	}
+	return newCandidateLink(newCandidate(latLng, SourcePath, confidenceMapView, false)), true
+}
+
+// extractPath reads the first `lat,lng` pair found anywhere in the path of a non-directions link.
+func extractPath(u *url.URL) (*GoogleMapsLink, bool) {
+	if _, ok := ParseRoute(u); ok {
+		return nil, false
+	}
+	latLng, swapped, err := latLng(u.Path, latLngURLPattern)
+	if err != nil {
+		return nil, false
+	}
+	return newCandidateLink(newCandidate(latLng, SourcePath, confidencePath, swapped)), true
+}
+
+// strictLatLng parses a `lat,lng` pair in this exact order, rejecting values out of range instead of swapping them.
+func strictLatLng(s string, pattern *regexp.Regexp) (LatLng, bool) {
+	matches := pattern.FindStringSubmatch(s)
+	if matches == nil {
+		return LatLng{}, false
+	}
+	lat, err := parsePointFromString(matches[1])
+	if err != nil {
+		return LatLng{}, false
+	}
+	lng, err := parsePointFromString(matches[2])
+	if err != nil {
+		return LatLng{}, false
+	}
+	latLng := LatLng{Latitude: lat, Longitude: lng}
+	if !latLng.Valid() {
+		return LatLng{}, false
+	}
+	return latLng, true
+}
+
+// lngLatLabel parses `lng,lat` optionally followed by `,label`.
+func lngLatLabel(v string) (LatLng, string, bool) {
+	parts := strings.SplitN(v, ",", 3)
+	if len(parts) < 2 {
+		return LatLng{}, "", false
+	}
+	latLng, ok := strictLatLng(parts[1]+","+parts[0], routeStopLatLngPattern)
+	if !ok {
+		return LatLng{}, "", false
+	}
+	if len(parts) == 3 {
+		return latLng, labelBeforeComma(parts[2]), true
+	}
+	return latLng, "", true
+}
+
+// latLngLabel parses `lat,lng` optionally followed by `,label`.
+func latLngLabel(v string) (LatLng, string, bool) {
+	parts := strings.SplitN(v, ",", 3)
+	if len(parts) < 2 {
+		return LatLng{}, "", false
+	}
+	latLng, ok := strictLatLng(parts[0]+","+parts[1], routeStopLatLngPattern)
+	if !ok {
+		return LatLng{}, "", false
+	}
+	if len(parts) == 3 {
+		return latLng, labelBeforeComma(parts[2]), true
+	}
+	return latLng, "", true
+}
+
+// labelBeforeComma returns the label up to the fields following it, such as the address.
+func labelBeforeComma(v string) string {
+	label, _, _ := strings.Cut(v, ",")
+	return strings.TrimSpace(label)
+}
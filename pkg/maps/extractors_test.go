@@ -0,0 +1,95 @@
+package maps
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoogleMapsFromURL(t *testing.T) {
+	testCases := []struct {
+		name           string
+		inputURL       string
+		expectedLatLng LatLng
+	}{
+		{
+			name:           "Query param q",
+			inputURL:       "https://maps.google.com/?q=52.2,21.0",
+			expectedLatLng: LatLng{Latitude: 52.2, Longitude: 21.0},
+		},
+		{
+			name:           "Query param q with loc prefix and label",
+			inputURL:       "https://maps.google.com/maps?q=loc:-33.8688,151.2093+(Sydney)",
+			expectedLatLng: LatLng{Latitude: -33.8688, Longitude: 151.2093},
+		},
+		{
+			name:           "Query param q wins over ll viewport",
+			inputURL:       "https://maps.google.com/maps?ll=50.0,19.0&q=52.2,21.0&z=14",
+			expectedLatLng: LatLng{Latitude: 52.2, Longitude: 21.0},
+		},
+		{
+			name:           "Search API query",
+			inputURL:       "https://www.google.com/maps/search/?api=1&query=47.5951518,-122.3316393",
+			expectedLatLng: LatLng{Latitude: 47.5951518, Longitude: -122.3316393},
+		},
+		{
+			name:           "Legacy ll param",
+			inputURL:       "https://maps.google.com/maps?ll=51.107885,17.038538&z=15",
+			expectedLatLng: LatLng{Latitude: 51.107885, Longitude: 17.038538},
+		},
+		{
+			name:           "Map API center param",
+			inputURL:       "https://www.google.com/maps/@?api=1&map_action=map&center=-33.712206,150.311941&zoom=12",
+			expectedLatLng: LatLng{Latitude: -33.712206, Longitude: 150.311941},
+		},
+		{
+			name:           "Search path",
+			inputURL:       "https://www.google.com/maps/search/51.107885,+17.038538?entry=tts",
+			expectedLatLng: LatLng{Latitude: 51.107885, Longitude: 17.038538},
+		},
+		{
+			name:           "Map view path",
+			inputURL:       "https://www.google.com/maps/@51.107885,17.038538,15z",
+			expectedLatLng: LatLng{Latitude: 51.107885, Longitude: 17.038538},
+		},
+		{
+			name:           "Map view path with both values valid latitudes",
+			inputURL:       "https://www.google.com/maps/@12.5,45.25,15z",
+			expectedLatLng: LatLng{Latitude: 12.5, Longitude: 45.25},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			link, ok := googleMapsFromURL(u)
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedLatLng, link.latLng)
+		})
+	}
+}
+
+func TestGoogleMapsFromURL_NotFound(t *testing.T) {
+	testCases := []struct {
+		name     string
+		inputURL string
+	}{
+		{name: "Address query", inputURL: "https://maps.google.com/?q=Rynek+Wroclaw"},
+		{name: "Out of range query", inputURL: "https://maps.google.com/?q=120.5,21.0"},
+		{name: "Short link", inputURL: "https://maps.app.goo.gl/LsERZt5ZbvMPqm92A"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			_, ok := googleMapsFromURL(u)
+			assert.False(t, ok)
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package maps
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Reasons a request gets rejected by the SafeTransport, wrapped in a FetchError.
+var (
+	ErrSchemeNotAllowed = errors.New("scheme not allowed")
+	ErrHostNotAllowed   = errors.New("host not allowed")
+	ErrPrivateAddress   = errors.New("private address not allowed")
+	ErrBodyTooLarge     = errors.New("response body too large")
+)
+
+// FetchError is returned when a request or its response is rejected for safety reasons.
+type FetchError struct {
+	URL string
+	Err error
+}
+
+func (e *FetchError) Error() string {
+	return fmt.Sprintf("rejected request to %s: %v", e.URL, e.Err)
+}
+
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+const (
+	// defaultMaxResponseSize caps every response body read through the SafeTransport.
+	defaultMaxResponseSize = 8 << 20
+	dialTimeout            = 5 * time.Second
+	// googleHostRegex matches Google domains in every country, e.g. google.com, google.pl or google.co.uk.
+	googleHostRegex = `(^|\.)google\.(com|[a-z]{2}|co\.[a-z]{2}|com\.[a-z]{2})$`
+)
+
+var googleHostPattern = regexp.MustCompile(googleHostRegex)
+
+// googleHosts are the hosts of Google Maps links and their short links, google.com standing for the Google domains
+// of every country matched by googleHostPattern.
+var googleHosts = []string{"goo.gl", "g.co", "google.com"}
+
+// DefaultAllowedHosts are the hosts of the map services links are fetched from, gathered from the hosts of every
+// service parsed with a fetch. Subdomains of every host are allowed too.
+var DefaultAllowedHosts = joinHosts(googleHosts, WazeHosts, AmapHosts, YandexHosts, TwoGISHosts, HereHosts, BingHosts, MapyCzHosts)
+
+func joinHosts(services ...[]string) []string {
+	var hosts []string
+	for _, service := range services {
+		hosts = append(hosts, service...)
+	}
+	return hosts
+}
+
+// blockedPrefixes are the ranges not covered by the netip.Addr predicates that must never be dialed.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// SafeTransport is an http.RoundTripper that only talks to allowed hosts on public addresses
+// and caps the size of response bodies. Redirects go through it as well, so every hop is checked.
+type SafeTransport struct {
+	allowedHosts    []string
+	maxResponseSize int64
+	base            http.RoundTripper
+}
+
+// SafeOpt configures a SafeTransport.
+type SafeOpt func(*SafeTransport)
+
+// WithAllowedHosts replaces the DefaultAllowedHosts.
+func WithAllowedHosts(hosts ...string) SafeOpt {
+	return func(t *SafeTransport) {
+		t.allowedHosts = hosts
+	}
+}
+
+// WithMaxResponseSize caps the number of bytes read from any response body.
+func WithMaxResponseSize(n int64) SafeOpt {
+	return func(t *SafeTransport) {
+		t.maxResponseSize = n
+	}
+}
+
+// NewSafeTransport constructs a SafeTransport dialing only public addresses, checked after DNS resolution.
+func NewSafeTransport(opts ...SafeOpt) *SafeTransport {
+	dialer := &net.Dialer{Timeout: dialTimeout, Control: controlPublicAddress}
+	t := &SafeTransport{
+		allowedHosts:    DefaultAllowedHosts,
+		maxResponseSize: defaultMaxResponseSize,
+		base: &http.Transport{
+			// Proxies from the environment would dial on our behalf, bypassing the address check.
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   dialTimeout,
+			ExpectContinueTimeout: time.Second,
+		},
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// NewSafeClient constructs an http.Client on top of a SafeTransport following at most maxRedirects redirects.
+func NewSafeClient(maxRedirects int, opts ...SafeOpt) *http.Client {
+	return safeClient(NewSafeTransport(opts...), maxRedirects)
+}
+
+func safeClient(transport http.RoundTripper, maxRedirects int) *http.Client {
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxRedirects {
+				return &FetchError{URL: req.URL.String(), Err: ErrTooManyRedirects}
+			}
+			return nil
+		},
+	}
+}
+
+func (t *SafeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return nil, &FetchError{URL: req.URL.String(), Err: ErrSchemeNotAllowed}
+	}
+	if !t.allowed(req.URL.Hostname()) {
+		return nil, &FetchError{URL: req.URL.String(), Err: ErrHostNotAllowed}
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &limitedBody{
+		ReadCloser: resp.Body,
+		remaining:  t.maxResponseSize,
+		url:        req.URL.String(),
+	}
+	return resp, nil
+}
+
+func (t *SafeTransport) allowed(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if googleHostPattern.MatchString(host) {
+		return true
+	}
+	for _, allowed := range t.allowedHosts {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// controlPublicAddress rejects connections to private, loopback and link-local addresses.
+// It runs right before connecting, so it sees the address the host name resolved to.
+func controlPublicAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return &FetchError{URL: address, Err: err}
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return &FetchError{URL: address, Err: err}
+	}
+	if !publicAddress(addr) {
+		return &FetchError{URL: address, Err: ErrPrivateAddress}
+	}
+	return nil
+}
+
+func publicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// limitedBody fails the read once more than the allowed number of bytes is received.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	url       string
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, &FetchError{URL: b.url, Err: ErrBodyTooLarge}
+	}
+	// Read one byte past the limit to tell a body of exactly the limit from a larger one.
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), &FetchError{URL: b.url, Err: ErrBodyTooLarge}
+	}
+	return n, err
+}
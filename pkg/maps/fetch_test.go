@@ -0,0 +1,177 @@
+package maps
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafeTransport_Rejects(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+	port := strings.TrimPrefix(testServer.URL, "http://127.0.0.1:")
+
+	testCases := []struct {
+		name          string
+		inputURL      string
+		opts          []SafeOpt
+		expectedError error
+	}{
+		{
+			name:          "Metadata endpoint",
+			inputURL:      "http://169.254.169.254/latest/meta-data/",
+			expectedError: ErrHostNotAllowed,
+		},
+		{
+			name:          "Unknown host",
+			inputURL:      "https://www.example.com/maps",
+			expectedError: ErrHostNotAllowed,
+		},
+		{
+			name:          "Lookalike host",
+			inputURL:      "https://google.com.example.com/maps",
+			expectedError: ErrHostNotAllowed,
+		},
+		{
+			name:          "Unsupported scheme",
+			inputURL:      "file:///etc/passwd",
+			expectedError: ErrSchemeNotAllowed,
+		},
+		{
+			name:          "Allowed host resolving to loopback",
+			inputURL:      "http://localhost:" + port + "/",
+			opts:          []SafeOpt{WithAllowedHosts("localhost")},
+			expectedError: ErrPrivateAddress,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpClient := NewSafeClient(3, tc.opts...)
+			req, err := http.NewRequestWithContext(context.Background(), "GET", tc.inputURL, http.NoBody)
+			require.NoError(t, err)
+
+			_, err = httpClient.Do(req)
+			require.ErrorIs(t, err, tc.expectedError)
+			var fetchErr *FetchError
+			assert.ErrorAs(t, err, &fetchErr)
+		})
+	}
+}
+
+// newSafeRewriteClient checks requests with a SafeTransport before handing them to the test server.
+func newSafeRewriteClient(t *testing.T, handler http.Handler, opts ...SafeOpt) *http.Client {
+	httpClient := newRewriteClient(t, handler)
+	transport := NewSafeTransport(opts...)
+	transport.base = httpClient.Transport
+	return safeClient(transport, 3)
+}
+
+func TestDefaultAllowedHosts(t *testing.T) {
+	transport := NewSafeTransport()
+
+	for _, hosts := range [][]string{WazeHosts, AmapHosts, YandexHosts, TwoGISHosts, HereHosts, BingHosts, MapyCzHosts} {
+		for _, host := range hosts {
+			assert.True(t, transport.allowed("www."+host), host)
+		}
+	}
+	assert.True(t, transport.allowed("maps.app.goo.gl"))
+	assert.True(t, transport.allowed("www.google.co.uk"))
+}
+
+func TestSafeTransport_Redirects(t *testing.T) {
+	httpClient := newSafeRewriteClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Host + r.URL.Path {
+		case "maps.app.goo.gl/internal":
+			http.Redirect(w, r, "http://10.0.0.1/admin", http.StatusFound)
+		case "maps.app.goo.gl/loop":
+			http.Redirect(w, r, "https://maps.app.goo.gl/loop", http.StatusFound)
+		case "maps.app.goo.gl/google":
+			http.Redirect(w, r, "https://www.google.co.uk/maps/place/51.5,-0.12", http.StatusFound)
+		case "www.google.co.uk/maps/place/51.5,-0.12":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	testCases := []struct {
+		name          string
+		inputURL      string
+		expectedError error
+	}{
+		{name: "Redirect to internal address", inputURL: "https://maps.app.goo.gl/internal", expectedError: ErrHostNotAllowed},
+		{name: "Redirect loop", inputURL: "https://maps.app.goo.gl/loop", expectedError: ErrTooManyRedirects},
+		{name: "Redirect to allowed host", inputURL: "https://maps.app.goo.gl/google"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := httpClient.Get(tc.inputURL)
+			if tc.expectedError != nil {
+				require.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func TestSafeTransport_BodyLimit(t *testing.T) {
+	httpClient := newSafeRewriteClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(strings.Repeat("x", 100)))
+	}), WithMaxResponseSize(64))
+
+	resp, err := httpClient.Get("https://www.google.com/maps")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.ErrorIs(t, err, ErrBodyTooLarge)
+	assert.Len(t, body, 64)
+
+	u, err := url.Parse("https://www.google.com/maps/place/Foo")
+	require.NoError(t, err)
+	_, err = HttpGetToInput(httpClient)(context.Background(), u)
+	require.ErrorIs(t, err, ErrBodyTooLarge)
+}
+
+func TestPublicAddress(t *testing.T) {
+	testCases := []struct {
+		addr     string
+		expected bool
+	}{
+		{addr: "142.250.185.78", expected: true},
+		{addr: "2a00:1450:401b:800::200e", expected: true},
+		{addr: "127.0.0.1"},
+		{addr: "10.1.2.3"},
+		{addr: "172.16.0.1"},
+		{addr: "192.168.1.1"},
+		{addr: "169.254.169.254"},
+		{addr: "100.64.0.1"},
+		{addr: "0.0.0.0"},
+		{addr: "::1"},
+		{addr: "fe80::1"},
+		{addr: "fd00::1"},
+		{addr: "::ffff:127.0.0.1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.addr, func(t *testing.T) {
+			assert.Equal(t, tc.expected, publicAddress(netip.MustParseAddr(tc.addr)))
+		})
+	}
+}
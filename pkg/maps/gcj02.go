@@ -0,0 +1,157 @@
+package maps
+
+import "math"
+
+// GCJ-02 is the coordinate system mandated for maps of mainland China, WGS84 shifted by a few hundred metres
+// with an obfuscated offset, and BD-09 is Baidu's further shift of GCJ-02. Neither has a closed-form inverse,
+// so WGS84 is recovered by iterating the forward shift.
+const (
+	// gcj02SemiMajorAxis and gcj02EccentricitySquared are of the Krasovsky 1940 ellipsoid the shift is computed on.
+	gcj02SemiMajorAxis       = 6378245.0
+	gcj02EccentricitySquared = 0.00669342162296594323
+	// bd09Shift is the angle factor of the BD-09 shift, in radians per degree.
+	bd09Shift = math.Pi * 3000 / 180
+	// shiftTolerance is the difference in degrees, below a millimetre, at which the inverse shifts stop iterating.
+	shiftTolerance     = 1e-10
+	maxShiftIterations = 30
+)
+
+// chinaBorder outlines mainland China along with Hainan, clockwise from the Argun river, to a few kilometres.
+// The coast runs offshore, short of the waters of Korea and Taiwan.
+var chinaBorder = []LatLng{
+	// Russia, along the Argun, the Amur and the Ussuri.
+	{53.33, 121.45}, {53.57, 123.27}, {53.00, 125.50}, {50.25, 127.50}, {49.00, 130.00}, {47.70, 130.90},
+	{47.90, 132.70}, {48.45, 134.75}, {46.80, 134.00}, {45.30, 133.10}, {45.00, 132.40}, {44.40, 131.25},
+	{42.90, 130.95}, {42.43, 130.60},
+	// North Korea, along the Tumen and the Yalu.
+	{42.85, 130.15}, {42.97, 129.85}, {42.25, 129.20}, {42.00, 128.05}, {41.45, 128.10}, {41.75, 127.00},
+	{41.10, 126.15}, {40.45, 124.90}, {40.10, 124.40}, {39.83, 124.15},
+	// The coast, Hong Kong and Macau being cut out by chinaExclusions.
+	{38.40, 122.50}, {37.40, 123.00}, {35.00, 120.80}, {33.00, 121.50}, {31.80, 122.30}, {30.70, 122.90},
+	{29.50, 122.60}, {28.40, 122.10}, {27.00, 120.75}, {25.45, 120.00}, {24.40, 118.55}, {23.40, 117.40},
+	{22.70, 115.90}, {22.45, 114.60}, {22.00, 114.30}, {21.80, 113.30}, {21.40, 112.00}, {20.90, 111.10},
+	// Hainan, then the Gulf of Tonkin.
+	{20.20, 111.20}, {19.30, 111.10}, {18.00, 110.30}, {17.90, 109.30}, {18.50, 108.40}, {19.50, 108.40},
+	{20.40, 109.30}, {21.30, 108.60}, {21.52, 107.98},
+	// Vietnam, Laos and Myanmar.
+	{21.65, 107.35}, {21.97, 106.72}, {22.85, 106.75}, {23.10, 105.90}, {23.40, 105.30}, {22.95, 104.80},
+	{22.50, 103.95}, {22.80, 103.40}, {22.55, 102.50}, {22.40, 102.15}, {21.60, 101.80}, {21.15, 101.15},
+	{21.45, 100.20}, {21.70, 99.95}, {22.10, 99.20}, {22.95, 99.50}, {23.70, 98.85}, {23.95, 97.65},
+	{24.80, 97.55}, {25.60, 98.20}, {26.50, 98.75}, {27.60, 98.70}, {28.20, 97.35},
+	// India, Bhutan and Nepal, along the lines of actual control.
+	{29.25, 96.10}, {28.55, 93.50}, {27.90, 91.60}, {28.30, 90.30}, {27.20, 88.95}, {27.85, 88.15},
+	{28.00, 86.90}, {28.30, 85.20}, {29.30, 83.90}, {30.20, 81.25}, {30.40, 80.20}, {31.00, 79.30},
+	{31.80, 78.75}, {32.60, 78.40}, {33.50, 78.80}, {34.40, 78.70}, {35.50, 77.80}, {35.90, 76.50},
+	// Pakistan, Afghanistan, Tajikistan, Kyrgyzstan and Kazakhstan.
+	{36.85, 75.40}, {37.25, 74.90}, {38.60, 74.85}, {39.40, 73.60}, {39.45, 73.50}, {39.70, 73.90},
+	{40.10, 74.90}, {40.55, 75.40}, {40.75, 76.50}, {41.10, 77.60}, {41.75, 78.90}, {42.05, 80.20},
+	{42.85, 80.40}, {43.20, 80.80}, {44.20, 80.35}, {45.00, 80.00}, {45.40, 82.60}, {46.20, 82.40},
+	{47.10, 83.10}, {47.20, 85.60}, {48.40, 86.60}, {49.15, 87.30},
+	// Mongolia, back to the Argun.
+	{48.60, 88.00}, {47.90, 90.10}, {46.90, 90.90}, {45.30, 90.85}, {45.00, 93.50}, {44.40, 95.30},
+	{42.75, 96.40}, {42.60, 101.80}, {41.60, 105.00}, {41.90, 106.80}, {42.45, 109.90}, {43.65, 111.95},
+	{44.75, 113.60}, {45.40, 115.70}, {46.40, 117.40}, {46.55, 118.50}, {46.70, 119.90}, {47.60, 119.00},
+	{47.80, 117.70}, {48.50, 116.00}, {49.85, 116.70}, {50.30, 119.20}, {51.30, 119.90}, {52.40, 120.70},
+}
+
+// chinaExclusions are Hong Kong and Macau, within chinaBorder but keeping WGS84 on their maps.
+var chinaExclusions = []BoundingBox{
+	// Hong Kong, tiled along the Shenzhen river, leaving out Shekou and Futian.
+	{South: 22.15, West: 113.82, North: 22.45, East: 114.50},
+	{South: 22.45, West: 113.96, North: 22.51, East: 114.05},
+	{South: 22.45, West: 114.05, North: 22.515, East: 114.15},
+	{South: 22.45, West: 114.15, North: 22.545, East: 114.25},
+	{South: 22.45, West: 114.25, North: 22.53, East: 114.44},
+	// Macau, leaving out Hengqin.
+	{South: 22.165, West: 113.528, North: 22.217, East: 113.598},
+	{South: 22.109, West: 113.553, North: 22.165, East: 113.598},
+}
+
+// InChina tells whether the location is in mainland China, where GCJ-02 is shifted from WGS84. Hong Kong, Macau,
+// Taiwan and the neighbouring countries are left out.
+func InChina(latLng LatLng) bool {
+	for _, box := range chinaExclusions {
+		if box.Contains(latLng) {
+			return false
+		}
+	}
+	return inPolygon(latLng, chinaBorder)
+}
+
+// inPolygon casts a ray east from the location, which is inside when it crosses the edges an odd number of times.
+func inPolygon(latLng LatLng, polygon []LatLng) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Latitude > latLng.Latitude) == (b.Latitude > latLng.Latitude) {
+			continue
+		}
+		crossing := a.Longitude + (latLng.Latitude-a.Latitude)/(b.Latitude-a.Latitude)*(b.Longitude-a.Longitude)
+		if latLng.Longitude < crossing {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// WGS84ToGCJ02 shifts a WGS84 location into GCJ-02. Locations outside of China are left as they are.
+func WGS84ToGCJ02(latLng LatLng) LatLng {
+	if !InChina(latLng) {
+		return latLng
+	}
+	x, y := latLng.Longitude-105, latLng.Latitude-35
+	dLat := -100 + 2*x + 3*y + 0.2*y*y + 0.1*x*y + 0.2*math.Sqrt(math.Abs(x)) +
+		(20*math.Sin(6*x*math.Pi)+20*math.Sin(2*x*math.Pi))*2/3 +
+		(20*math.Sin(y*math.Pi)+40*math.Sin(y/3*math.Pi))*2/3 +
+		(160*math.Sin(y/12*math.Pi)+320*math.Sin(y*math.Pi/30))*2/3
+	dLng := 300 + x + 2*y + 0.1*x*x + 0.1*x*y + 0.1*math.Sqrt(math.Abs(x)) +
+		(20*math.Sin(6*x*math.Pi)+20*math.Sin(2*x*math.Pi))*2/3 +
+		(20*math.Sin(x*math.Pi)+40*math.Sin(x/3*math.Pi))*2/3 +
+		(150*math.Sin(x/12*math.Pi)+300*math.Sin(x/30*math.Pi))*2/3
+	// The offsets are in metres on the ellipsoid, turned into degrees at the latitude.
+	radLat := latLng.Latitude / 180 * math.Pi
+	magic := 1 - gcj02EccentricitySquared*math.Sin(radLat)*math.Sin(radLat)
+	sqrtMagic := math.Sqrt(magic)
+	dLat = dLat * 180 / (gcj02SemiMajorAxis * (1 - gcj02EccentricitySquared) / (magic * sqrtMagic) * math.Pi)
+	dLng = dLng * 180 / (gcj02SemiMajorAxis / sqrtMagic * math.Cos(radLat) * math.Pi)
+	return LatLng{Latitude: latLng.Latitude + dLat, Longitude: latLng.Longitude + dLng}
+}
+
+// GCJ02ToWGS84 reverses WGS84ToGCJ02 to below a millimetre.
+func GCJ02ToWGS84(latLng LatLng) LatLng {
+	return invertShift(latLng, WGS84ToGCJ02)
+}
+
+// GCJ02ToBD09 shifts a GCJ-02 location into Baidu's BD-09.
+func GCJ02ToBD09(latLng LatLng) LatLng {
+	x, y := latLng.Longitude, latLng.Latitude
+	z := math.Sqrt(x*x+y*y) + 0.00002*math.Sin(y*bd09Shift)
+	theta := math.Atan2(y, x) + 0.000003*math.Cos(x*bd09Shift)
+	return LatLng{Latitude: z*math.Sin(theta) + 0.006, Longitude: z*math.Cos(theta) + 0.0065}
+}
+
+// BD09ToGCJ02 reverses GCJ02ToBD09 to below a millimetre.
+func BD09ToGCJ02(latLng LatLng) LatLng {
+	return invertShift(latLng, GCJ02ToBD09)
+}
+
+// BD09ToWGS84 reverses both the BD-09 and the GCJ-02 shifts.
+func BD09ToWGS84(latLng LatLng) LatLng {
+	return GCJ02ToWGS84(BD09ToGCJ02(latLng))
+}
+
+// invertShift finds the location the shift maps to the target, starting from the target itself
+// and correcting it by the remaining difference, which converges quickly as the shifts vary slowly.
+func invertShift(target LatLng, shift func(LatLng) LatLng) LatLng {
+	latLng := target
+	for i := 0; i < maxShiftIterations; i++ {
+		shifted := shift(latLng)
+		dLat, dLng := target.Latitude-shifted.Latitude, target.Longitude-shifted.Longitude
+		latLng.Latitude += dLat
+		latLng.Longitude += dLng
+		if math.Abs(dLat) < shiftTolerance && math.Abs(dLng) < shiftTolerance {
+			break
+		}
+	}
+	return latLng
+}
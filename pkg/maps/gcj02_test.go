@@ -0,0 +1,91 @@
+package maps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWGS84ToGCJ02(t *testing.T) {
+	testCases := []struct {
+		name          string
+		wgs84         LatLng
+		expectedGCJ02 LatLng
+	}{
+		{name: "Shanghai", wgs84: LatLng{Latitude: 31.1774276, Longitude: 121.5272106}, expectedGCJ02: LatLng{Latitude: 31.17530398364597, Longitude: 121.531541859215}},
+		{name: "Shenzhen", wgs84: LatLng{Latitude: 22.543847, Longitude: 113.912316}, expectedGCJ02: LatLng{Latitude: 22.540796131694766, Longitude: 113.9171764808363}},
+		{name: "Beijing", wgs84: LatLng{Latitude: 39.911954, Longitude: 116.377817}, expectedGCJ02: LatLng{Latitude: 39.91334545536069, Longitude: 116.38404722455657}},
+		{name: "Outside of China", wgs84: LatLng{Latitude: 51.1069402, Longitude: 17.0772095}, expectedGCJ02: LatLng{Latitude: 51.1069402, Longitude: 17.0772095}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gcj02 := WGS84ToGCJ02(tc.wgs84)
+
+			assert.InDelta(t, tc.expectedGCJ02.Latitude, gcj02.Latitude, 1e-12)
+			assert.InDelta(t, tc.expectedGCJ02.Longitude, gcj02.Longitude, 1e-12)
+			wgs84 := GCJ02ToWGS84(gcj02)
+			assert.InDelta(t, tc.wgs84.Latitude, wgs84.Latitude, 1e-9)
+			assert.InDelta(t, tc.wgs84.Longitude, wgs84.Longitude, 1e-9)
+		})
+	}
+}
+
+func TestInChina(t *testing.T) {
+	testCases := []struct {
+		name     string
+		latLng   LatLng
+		expected bool
+	}{
+		{name: "Beijing", latLng: LatLng{Latitude: 39.9042, Longitude: 116.4074}, expected: true},
+		{name: "Shanghai", latLng: LatLng{Latitude: 31.2304, Longitude: 121.4737}, expected: true},
+		{name: "Shenzhen Futian", latLng: LatLng{Latitude: 22.5431, Longitude: 114.0579}, expected: true},
+		{name: "Shenzhen Shekou", latLng: LatLng{Latitude: 22.4846, Longitude: 113.9169}, expected: true},
+		{name: "Zhuhai", latLng: LatLng{Latitude: 22.2710, Longitude: 113.5767}, expected: true},
+		{name: "Sanya", latLng: LatLng{Latitude: 18.2528, Longitude: 109.5120}, expected: true},
+		{name: "Harbin", latLng: LatLng{Latitude: 45.8038, Longitude: 126.5350}, expected: true},
+		{name: "Dandong", latLng: LatLng{Latitude: 40.1292, Longitude: 124.3947}, expected: true},
+		{name: "Kashgar", latLng: LatLng{Latitude: 39.4677, Longitude: 75.9938}, expected: true},
+		{name: "Lhasa", latLng: LatLng{Latitude: 29.6520, Longitude: 91.1721}, expected: true},
+		{name: "Hong Kong Central", latLng: LatLng{Latitude: 22.2819, Longitude: 114.1582}, expected: false},
+		{name: "Hong Kong Yuen Long", latLng: LatLng{Latitude: 22.4445, Longitude: 114.0222}, expected: false},
+		{name: "Hong Kong Sheung Shui", latLng: LatLng{Latitude: 22.5010, Longitude: 114.1280}, expected: false},
+		{name: "Macau", latLng: LatLng{Latitude: 22.1987, Longitude: 113.5439}, expected: false},
+		{name: "Taipei", latLng: LatLng{Latitude: 25.0330, Longitude: 121.5654}, expected: false},
+		{name: "Penghu", latLng: LatLng{Latitude: 23.5711, Longitude: 119.5793}, expected: false},
+		{name: "Seoul", latLng: LatLng{Latitude: 37.5665, Longitude: 126.9780}, expected: false},
+		{name: "Pyongyang", latLng: LatLng{Latitude: 39.0392, Longitude: 125.7625}, expected: false},
+		{name: "Chongjin", latLng: LatLng{Latitude: 41.7956, Longitude: 129.7759}, expected: false},
+		{name: "Vladivostok", latLng: LatLng{Latitude: 43.1155, Longitude: 131.8855}, expected: false},
+		{name: "Osaka", latLng: LatLng{Latitude: 34.6937, Longitude: 135.5023}, expected: false},
+		{name: "Ulaanbaatar", latLng: LatLng{Latitude: 47.8864, Longitude: 106.9057}, expected: false},
+		{name: "Hanoi", latLng: LatLng{Latitude: 21.0278, Longitude: 105.8342}, expected: false},
+		{name: "Lang Son", latLng: LatLng{Latitude: 21.8537, Longitude: 106.7615}, expected: false},
+		{name: "Bangkok", latLng: LatLng{Latitude: 13.7563, Longitude: 100.5018}, expected: false},
+		{name: "Singapore", latLng: LatLng{Latitude: 1.3521, Longitude: 103.8198}, expected: false},
+		{name: "Kathmandu", latLng: LatLng{Latitude: 27.7172, Longitude: 85.3240}, expected: false},
+		{name: "Bishkek", latLng: LatLng{Latitude: 42.8746, Longitude: 74.5698}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, InChina(tc.latLng))
+		})
+	}
+}
+
+func TestBD09(t *testing.T) {
+	gcj02 := LatLng{Latitude: 39.91334545536069, Longitude: 116.38404722455657}
+
+	bd09 := GCJ02ToBD09(gcj02)
+
+	// BD-09 shifts further by about 0.006 degrees in both directions.
+	assert.InDelta(t, gcj02.Latitude+0.006, bd09.Latitude, 1e-3)
+	assert.InDelta(t, gcj02.Longitude+0.0065, bd09.Longitude, 1e-3)
+	back := BD09ToGCJ02(bd09)
+	assert.InDelta(t, gcj02.Latitude, back.Latitude, 1e-9)
+	assert.InDelta(t, gcj02.Longitude, back.Longitude, 1e-9)
+	wgs84 := BD09ToWGS84(bd09)
+	assert.InDelta(t, 39.911954, wgs84.Latitude, 1e-9)
+	assert.InDelta(t, 116.377817, wgs84.Longitude, 1e-9)
+}
@@ -0,0 +1,203 @@
+package maps
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInvalidGeoURI is returned for geo URIs not following RFC 5870.
+	ErrInvalidGeoURI = errors.New("invalid geo uri")
+	// ErrUnsupportedCRS is returned for geo URIs in a coordinate reference system other than WGS84.
+	ErrUnsupportedCRS = errors.New("unsupported coordinate reference system")
+	// ErrGeoURIAddress is returned for the location of Android geo URIs searching for an address rather than pointing at coordinates.
+	ErrGeoURIAddress = errors.New("geo uri searches for an address")
+)
+
+const (
+	// GeoScheme is the scheme of geo URIs, e.g. `geo:52.2,21.0;u=35`.
+	GeoScheme = "geo"
+	// geoCRSWGS84 is the only coordinate reference system registered for geo URIs, and the default one.
+	geoCRSWGS84 = "wgs84"
+	geoCRSParam = "crs"
+	// geoUncertaintyParam is the radius of uncertainty of the location in metres.
+	geoUncertaintyParam = "u"
+	// geoNumberRegex matches the numbers of the coordinates, which have neither exponents nor a leading `+`.
+	geoNumberRegex = `^-?\d+(?:\.\d+)?$`
+)
+
+var geoNumberPattern = regexp.MustCompile(geoNumberRegex)
+
+// GeoURI is a location given as a geo URI of RFC 5870, e.g. `geo:52.2,21.0,100;u=35`, along with the `q=` and `z=`
+// extensions of Android, e.g. `geo:0,0?q=52.2,21.0(Label)` or `geo:0,0?q=Plac+Defilad+1,+Warszawa`.
+type GeoURI struct {
+	Latitude  float64
+	Longitude float64
+	// Altitude is the height above the WGS84 ellipsoid in metres, nil when not given.
+	Altitude *float64
+	// Uncertainty is the radius of the circle the location lies in in metres, nil when unknown.
+	Uncertainty *float64
+	// Label is the name given to the location in the `q=lat,lng(Label)` search of Android.
+	Label string
+	// Query is the address of the `q=` search of Android, given along with `geo:0,0`.
+	Query string
+	// Zoom is the `z=` zoom level of Android, 0 when not given.
+	Zoom int
+}
+
+// LatLng returns the location of the URI, failing with ErrGeoURIAddress for URIs searching for an address.
+func (g *GeoURI) LatLng() (LatLng, error) {
+	if g.Query != "" {
+		return LatLng{}, fmt.Errorf("failed to locate %q: %w", g.Query, ErrGeoURIAddress)
+	}
+	return LatLng{Latitude: g.Latitude, Longitude: g.Longitude}, nil
+}
+
+// Name returns the label of the location when the URI carries one.
+func (g *GeoURI) Name() string {
+	return g.Label
+}
+
+// SearchURL returns the Google Maps search of the address of URIs searching for one.
+func (g *GeoURI) SearchURL() (*url.URL, bool) {
+	if g.Query == "" {
+		return nil, false
+	}
+	return GoogleMapsSearchURL(g.Query), true
+}
+
+// URL formats the URI, leaving out the default WGS84 reference system. Labelled locations get the `q=lat,lng(Label)`
+// search of Android as well, so that the label shows up in the apps understanding it.
+func (g *GeoURI) URL() *url.URL {
+	coordinates := []string{formatGeoFloat(g.Latitude), formatGeoFloat(g.Longitude)}
+	if g.Altitude != nil {
+		coordinates = append(coordinates, formatGeoFloat(*g.Altitude))
+	}
+	opaque := strings.Join(coordinates, ",")
+	if g.Uncertainty != nil {
+		opaque += ";" + geoUncertaintyParam + "=" + formatGeoFloat(*g.Uncertainty)
+	}
+	var query []string
+	switch {
+	case g.Query != "":
+		query = append(query, "q="+url.QueryEscape(g.Query))
+	case g.Label != "":
+		query = append(query, fmt.Sprintf("q=%s,%s(%s)", formatGeoFloat(g.Latitude), formatGeoFloat(g.Longitude), url.QueryEscape(g.Label)))
+	}
+	if g.Zoom > 0 {
+		query = append(query, "z="+strconv.Itoa(g.Zoom))
+	}
+	return &url.URL{Scheme: GeoScheme, Opaque: opaque, RawQuery: strings.Join(query, "&")}
+}
+
+// String formats the URI, e.g. `geo:52.2,21;u=35`.
+func (g *GeoURI) String() string {
+	return g.URL().String()
+}
+
+func formatGeoFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// GeoURIFromLocation constructs a new geo URI from location, keeping the name of named locations as the label.
+func GeoURIFromLocation(l Location) (*GeoURI, error) {
+	latLng, err := l.LatLng()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to extract lat lng from location")
+	}
+	g := &GeoURI{Latitude: latLng.Latitude, Longitude: latLng.Longitude}
+	if named, ok := l.(interface{ Name() string }); ok {
+		g.Label = named.Name()
+	}
+	return g, nil
+}
+
+// ParseGeoURIFromURL parses a `geo:lat,lng[,alt][;crs=wgs84][;u=uncertainty][;param=value]` URI, ignoring the
+// parameters it does not know. The Android `q=` search replaces `geo:0,0` when it holds coordinates, and labels
+// the location otherwise.
+func ParseGeoURIFromURL(u *url.URL) (*GeoURI, error) {
+	if !strings.EqualFold(u.Scheme, GeoScheme) || u.Opaque == "" {
+		return nil, fmt.Errorf("failed to parse url: %s, error: %w", u.String(), ErrInvalidGeoURI)
+	}
+	path := strings.Split(u.Opaque, ";")
+	g, err := parseGeoCoordinates(path[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse coordinates of url: %s, error: %w", u.String(), err)
+	}
+	for i, param := range path[1:] {
+		name, value, _ := strings.Cut(param, "=")
+		value, err := url.PathUnescape(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unescape parameter %s of url: %s, error: %w", name, u.String(), ErrInvalidGeoURI)
+		}
+		switch strings.ToLower(name) {
+		case geoCRSParam:
+			// The reference system comes first, before the uncertainty and the other parameters.
+			if i != 0 {
+				return nil, fmt.Errorf("failed to parse crs of url: %s, error: %w", u.String(), ErrInvalidGeoURI)
+			}
+			if !strings.EqualFold(value, geoCRSWGS84) {
+				return nil, fmt.Errorf("failed to parse crs %s of url: %s, error: %w", value, u.String(), ErrUnsupportedCRS)
+			}
+		case geoUncertaintyParam:
+			uncertainty, err := strconv.ParseFloat(value, 64)
+			if err != nil || uncertainty < 0 {
+				return nil, fmt.Errorf("failed to parse uncertainty of url: %s, error: %w", u.String(), ErrInvalidGeoURI)
+			}
+			g.Uncertainty = &uncertainty
+		}
+	}
+
+	query := u.Query()
+	if zoom, err := strconv.Atoi(query.Get("z")); err == nil && zoom > 0 {
+		g.Zoom = zoom
+	}
+	q := strings.TrimSpace(query.Get("q"))
+	if q == "" {
+		return g, nil
+	}
+	if latLng, ok := strictLatLng(q, queryLatLngPattern); ok {
+		g.Latitude, g.Longitude = latLng.Latitude, latLng.Longitude
+		g.Label = strings.TrimSpace(queryLatLngPattern.FindStringSubmatch(q)[3])
+		return g, nil
+	}
+	// Android searches for the address around the coordinates, which are left at 0,0 when there is nothing to search around.
+	if g.Latitude == 0 && g.Longitude == 0 {
+		g.Query = q
+	} else {
+		g.Label = q
+	}
+	return g, nil
+}
+
+// parseGeoCoordinates parses the `lat,lng[,alt]` coordinates of a geo URI.
+func parseGeoCoordinates(s string) (*GeoURI, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, ErrInvalidGeoURI
+	}
+	values := make([]float64, len(parts))
+	for i, part := range parts {
+		if !geoNumberPattern.MatchString(part) {
+			return nil, ErrInvalidGeoURI
+		}
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return nil, ErrInvalidGeoURI
+		}
+		values[i] = v
+	}
+	g := &GeoURI{Latitude: values[0], Longitude: values[1]}
+	if !(LatLng{Latitude: g.Latitude, Longitude: g.Longitude}).Valid() {
+		return nil, ErrInvalidGeoURI
+	}
+	if len(values) == 3 {
+		g.Altitude = &values[2]
+	}
+	return g, nil
+}
@@ -0,0 +1,173 @@
+package maps
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGeoURIFromURL(t *testing.T) {
+	altitude, uncertainty := 100.0, 35.0
+
+	testCases := []struct {
+		name          string
+		inputURL      string
+		expected      *GeoURI
+		expectedError error
+	}{
+		{
+			name:     "Coordinates",
+			inputURL: "geo:52.2297,21.0122",
+			expected: &GeoURI{Latitude: 52.2297, Longitude: 21.0122},
+		},
+		{
+			name:     "Altitude and uncertainty",
+			inputURL: "geo:52.2297,21.0122,100;u=35",
+			expected: &GeoURI{Latitude: 52.2297, Longitude: 21.0122, Altitude: &altitude, Uncertainty: &uncertainty},
+		},
+		{
+			name:     "Explicit WGS84 and unknown parameters",
+			inputURL: "geo:-33.8688,151.2093;CRS=WGS84;U=35;foo=bar%20baz",
+			expected: &GeoURI{Latitude: -33.8688, Longitude: 151.2093, Uncertainty: &uncertainty},
+		},
+		{
+			name:     "Android labelled coordinates",
+			inputURL: "geo:0,0?q=51.1069402,17.0772095(Hala%20Stulecia)&z=17",
+			expected: &GeoURI{Latitude: 51.1069402, Longitude: 17.0772095, Label: "Hala Stulecia", Zoom: 17},
+		},
+		{
+			name:     "Android address search",
+			inputURL: "geo:0,0?q=Plac+Defilad+1,+Warszawa",
+			expected: &GeoURI{Query: "Plac Defilad 1, Warszawa"},
+		},
+		{
+			name:     "Android search around coordinates",
+			inputURL: "geo:51.1069402,17.0772095?q=Hala+Stulecia",
+			expected: &GeoURI{Latitude: 51.1069402, Longitude: 17.0772095, Label: "Hala Stulecia"},
+		},
+		{
+			name:          "Other reference system",
+			inputURL:      "geo:4.4,5.5;crs=moon-2011",
+			expectedError: ErrUnsupportedCRS,
+		},
+		{
+			name:          "Reference system after uncertainty",
+			inputURL:      "geo:52.2,21.0;u=35;crs=wgs84",
+			expectedError: ErrInvalidGeoURI,
+		},
+		{
+			name:          "Negative uncertainty",
+			inputURL:      "geo:52.2,21.0;u=-1",
+			expectedError: ErrInvalidGeoURI,
+		},
+		{
+			name:          "Latitude out of range",
+			inputURL:      "geo:91,21.0",
+			expectedError: ErrInvalidGeoURI,
+		},
+		{
+			name:          "Exponent",
+			inputURL:      "geo:5e1,21.0",
+			expectedError: ErrInvalidGeoURI,
+		},
+		{
+			name:          "Missing longitude",
+			inputURL:      "geo:52.2",
+			expectedError: ErrInvalidGeoURI,
+		},
+		{
+			name:          "Other scheme",
+			inputURL:      "https://www.google.com/maps?q=52.2,21.0",
+			expectedError: ErrInvalidGeoURI,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			g, err := ParseGeoURIFromURL(u)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, g)
+		})
+	}
+}
+
+func TestGeoURI_LatLng(t *testing.T) {
+	g := &GeoURI{Query: "Plac Defilad 1, Warszawa"}
+
+	_, err := g.LatLng()
+
+	assert.ErrorIs(t, err, ErrGeoURIAddress)
+	search, ok := g.SearchURL()
+	require.True(t, ok)
+	assert.Equal(t, "https://www.google.com/maps/search/?api=1&query=Plac+Defilad+1%2C+Warszawa", search.String())
+}
+
+func TestGeoURI_URL(t *testing.T) {
+	altitude, uncertainty := 100.0, 35.5
+
+	testCases := []struct {
+		name        string
+		geo         *GeoURI
+		expectedURL string
+	}{
+		{
+			name:        "Coordinates",
+			geo:         &GeoURI{Latitude: 52.2297, Longitude: 21.0122},
+			expectedURL: "geo:52.2297,21.0122",
+		},
+		{
+			name:        "Altitude and uncertainty",
+			geo:         &GeoURI{Latitude: -33.8688, Longitude: 151.2093, Altitude: &altitude, Uncertainty: &uncertainty},
+			expectedURL: "geo:-33.8688,151.2093,100;u=35.5",
+		},
+		{
+			name:        "Label and zoom",
+			geo:         &GeoURI{Latitude: 51.1069402, Longitude: 17.0772095, Label: "Hala Stulecia (Wrocław)", Zoom: 17},
+			expectedURL: "geo:51.1069402,17.0772095?q=51.1069402,17.0772095(Hala+Stulecia+%28Wroc%C5%82aw%29)&z=17",
+		},
+		{
+			name:        "Label with an ampersand",
+			geo:         &GeoURI{Latitude: 51.5072, Longitude: -0.1276, Label: "Fish & Chips"},
+			expectedURL: "geo:51.5072,-0.1276?q=51.5072,-0.1276(Fish+%26+Chips)",
+		},
+		{
+			name:        "Label with a plus",
+			geo:         &GeoURI{Latitude: 51.5072, Longitude: -0.1276, Label: "A+B"},
+			expectedURL: "geo:51.5072,-0.1276?q=51.5072,-0.1276(A%2BB)",
+		},
+		{
+			name:        "Address search",
+			geo:         &GeoURI{Query: "Plac Defilad 1, Warszawa"},
+			expectedURL: "geo:0,0?q=Plac+Defilad+1%2C+Warszawa",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedURL, tc.geo.String())
+
+			parsed, err := ParseGeoURIFromURL(tc.geo.URL())
+			require.NoError(t, err)
+			assert.Equal(t, tc.geo, parsed)
+		})
+	}
+}
+
+func TestGeoURIFromLocation(t *testing.T) {
+	link := &HereLink{latLng: LatLng{Latitude: 50.08804, Longitude: 14.42076}, name: "Old Town Square"}
+
+	g, err := GeoURIFromLocation(link)
+
+	require.NoError(t, err)
+	assert.Equal(t, "geo:50.08804,14.42076?q=50.08804,14.42076(Old+Town+Square)", g.String())
+}
@@ -0,0 +1,127 @@
+package maps
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidGeohash is returned for geohashes with characters outside of the geohash alphabet.
+var ErrInvalidGeohash = errors.New("invalid geohash")
+
+// GeohashHosts are the hosts of the geohash links, e.g. `geohash.org/u3h4fp2`.
+var GeohashHosts = []string{"geohash.org"}
+
+const (
+	geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+	// MaxGeohashPrecision is the number of characters past which the cells get smaller than floating point precision.
+	MaxGeohashPrecision = 12
+	// geohashTextRegex matches the geohashes named as such in text, e.g. `geohash: u3h4fp2`.
+	geohashTextRegex = `(?i)\bgeohash\s*[:=]?\s*([0-9b-hjkmnp-z]{1,12})\b`
+)
+
+var geohashTextPattern = regexp.MustCompile(geohashTextRegex)
+
+// Geohash is a location encoded as a geohash, e.g. `u3h4fp2`, standing for the center of its cell.
+type Geohash string
+
+// LatLng returns the center of the cell of the geohash.
+func (g Geohash) LatLng() (LatLng, error) {
+	box, err := g.Bounds()
+	if err != nil {
+		return LatLng{}, err
+	}
+	return box.Center(), nil
+}
+
+// Bounds returns the cell of the geohash, which gets 32 times smaller with every character.
+func (g Geohash) Bounds() (BoundingBox, error) {
+	if g == "" {
+		return BoundingBox{}, fmt.Errorf("failed to decode empty geohash: %w", ErrInvalidGeohash)
+	}
+	box := BoundingBox{South: -90, West: -180, North: 90, East: 180}
+	// Bits alternate between longitude and latitude, starting with longitude.
+	even := true
+	for i, c := range strings.ToLower(string(g)) {
+		digit := strings.IndexRune(geohashAlphabet, c)
+		if digit < 0 {
+			return BoundingBox{}, fmt.Errorf("failed to decode geohash %s at %d: %w", g, i, ErrInvalidGeohash)
+		}
+		for bit := 4; bit >= 0; bit-- {
+			set := digit>>bit&1 == 1
+			if even {
+				mid := (box.West + box.East) / 2
+				if set {
+					box.West = mid
+				} else {
+					box.East = mid
+				}
+			} else {
+				mid := (box.South + box.North) / 2
+				if set {
+					box.South = mid
+				} else {
+					box.North = mid
+				}
+			}
+			even = !even
+		}
+	}
+	return box, nil
+}
+
+// EncodeGeohash encodes the location into a geohash of the given number of characters.
+func EncodeGeohash(latLng LatLng, precision int) Geohash {
+	if precision > MaxGeohashPrecision {
+		precision = MaxGeohashPrecision
+	}
+	box := BoundingBox{South: -90, West: -180, North: 90, East: 180}
+	var sb strings.Builder
+	even := true
+	for sb.Len() < precision {
+		digit := 0
+		for bit := 4; bit >= 0; bit-- {
+			if even {
+				mid := (box.West + box.East) / 2
+				if latLng.Longitude >= mid {
+					digit |= 1 << bit
+					box.West = mid
+				} else {
+					box.East = mid
+				}
+			} else {
+				mid := (box.South + box.North) / 2
+				if latLng.Latitude >= mid {
+					digit |= 1 << bit
+					box.South = mid
+				} else {
+					box.North = mid
+				}
+			}
+			even = !even
+		}
+		sb.WriteByte(geohashAlphabet[digit])
+	}
+	return Geohash(sb.String())
+}
+
+// ParseGeohashFromURL extracts the geohash from the path of a `geohash.org/<geohash>` link.
+func ParseGeohashFromURL(u *url.URL) (Geohash, error) {
+	g := Geohash(strings.Trim(u.Path, "/"))
+	if _, err := g.Bounds(); err != nil {
+		return "", fmt.Errorf("failed to parse geohash of url: %s, error: %w", u.String(), err)
+	}
+	return g, nil
+}
+
+// FindGeohash finds the first geohash named as such in the text, e.g. `geohash: u3h4fp2`, along with the offset it starts at.
+func FindGeohash(text string) (Geohash, int, bool) {
+	matches := geohashTextPattern.FindStringSubmatchIndex(text)
+	if matches == nil {
+		return "", -1, false
+	}
+	return Geohash(strings.ToLower(text[matches[2]:matches[3]])), matches[0], true
+}
@@ -0,0 +1,79 @@
+package maps
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeohash_Bounds(t *testing.T) {
+	testCases := []struct {
+		geohash        Geohash
+		expectedLatLng LatLng
+		// expectedError is empty for valid geohashes.
+		expectedError error
+		// expectedHeight is the height of the cell in degrees of latitude.
+		expectedHeight float64
+	}{
+		{geohash: "ezs42", expectedLatLng: LatLng{Latitude: 42.6050, Longitude: -5.6030}, expectedHeight: 180.0 / (1 << 12)},
+		{geohash: "u4pruydqqvj", expectedLatLng: LatLng{Latitude: 57.64911, Longitude: 10.40744}, expectedHeight: 180.0 / (1 << 27)},
+		{geohash: "U4PRUYDQQVJ", expectedLatLng: LatLng{Latitude: 57.64911, Longitude: 10.40744}, expectedHeight: 180.0 / (1 << 27)},
+		{geohash: "s", expectedLatLng: LatLng{Latitude: 22.5, Longitude: 22.5}, expectedHeight: 45},
+		{geohash: "ezs4a", expectedError: ErrInvalidGeohash},
+		{geohash: "", expectedError: ErrInvalidGeohash},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.geohash), func(t *testing.T) {
+			box, err := tc.geohash.Bounds()
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expectedHeight, box.North-box.South, 1e-12)
+			latLng, err := tc.geohash.LatLng()
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expectedLatLng.Latitude, latLng.Latitude, 5e-4)
+			assert.InDelta(t, tc.expectedLatLng.Longitude, latLng.Longitude, 5e-4)
+		})
+	}
+}
+
+func TestEncodeGeohash(t *testing.T) {
+	assert.Equal(t, Geohash("u4pruydqqvj"), EncodeGeohash(LatLng{Latitude: 57.64911, Longitude: 10.40744}, 11))
+	assert.Equal(t, Geohash("ezs42"), EncodeGeohash(LatLng{Latitude: 42.605, Longitude: -5.603}, 5))
+	assert.Len(t, EncodeGeohash(LatLng{Latitude: 57.64911, Longitude: 10.40744}, 20), MaxGeohashPrecision)
+
+	// Every location lies within the cell of its geohash.
+	for precision := 1; precision <= MaxGeohashPrecision; precision++ {
+		latLng := LatLng{Latitude: 51.1069402, Longitude: 17.0772095}
+		box, err := EncodeGeohash(latLng, precision).Bounds()
+		require.NoError(t, err)
+		assert.True(t, box.South <= latLng.Latitude && latLng.Latitude < box.North, "precision %d", precision)
+		assert.True(t, box.West <= latLng.Longitude && latLng.Longitude < box.East, "precision %d", precision)
+	}
+}
+
+func TestParseGeohashFromURL(t *testing.T) {
+	u, err := url.Parse("http://geohash.org/u4pruydqqvj")
+	require.NoError(t, err)
+
+	geohash, err := ParseGeohashFromURL(u)
+
+	require.NoError(t, err)
+	assert.Equal(t, Geohash("u4pruydqqvj"), geohash)
+}
+
+func TestFindGeohash(t *testing.T) {
+	geohash, start, ok := FindGeohash("depot at Geohash: U4PRUYDQ, gate 2")
+	assert.True(t, ok)
+	assert.Equal(t, Geohash("u4pruydq"), geohash)
+	assert.Equal(t, 9, start)
+
+	_, _, ok = FindGeohash("u4pruydq")
+	assert.False(t, ok)
+}
@@ -0,0 +1,76 @@
+package maps
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// HereHosts are the hosts HERE WeGo links are shared from.
+var HereHosts = []string{"here.com"}
+
+const (
+	// hereLocationPath prefixes the `lat,lng,label` of share links, e.g. `share.here.com/l/52.5,13.4,Berlin`.
+	hereLocationPath = "/l/"
+	// hereShortLinkPath prefixes the share links of places, which redirect to WeGo.
+	hereShortLinkPath = "/p/"
+)
+
+// HereLink is a location shared from HERE WeGo.
+type HereLink struct {
+	latLng LatLng
+	name   string
+}
+
+func (l *HereLink) LatLng() (LatLng, error) {
+	return l.latLng, nil
+}
+
+// Name returns the name of the place when the link carries one.
+func (l *HereLink) Name() string {
+	return l.name
+}
+
+// ParseHereFromURL extracts HereLink from the `share.here.com/l/lat,lng,label` share links, the destination of
+// `wego.here.com/directions/` routes and the `wego.here.com/?map=lat,lng,zoom` map center.
+// Share links of places are followed to WeGo with toTarget.
+func ParseHereFromURL(ctx context.Context, u *url.URL, toTarget UrlToTarget) (*HereLink, error) {
+	if strings.HasPrefix(u.Path, hereShortLinkPath) && u.Query().Get("map") == "" {
+		target, err := toTarget(ctx, u)
+		if err != nil {
+			return nil, fmt.Errorf("failed to follow short link: %s, error: %w", u.String(), err)
+		}
+		u = target
+	}
+	if location, ok := strings.CutPrefix(u.Path, hereLocationPath); ok {
+		if latLng, name, ok := latLngLabel(location); ok {
+			return &HereLink{latLng: latLng, name: name}, nil
+		}
+	}
+	if link, ok := hereRouteDestination(u.Path); ok {
+		return link, nil
+	}
+	if latLng, _, ok := latLngLabel(u.Query().Get("map")); ok {
+		return &HereLink{latLng: latLng}, nil
+	}
+	return nil, fmt.Errorf("failed to find lat lng for url: %s", u.String())
+}
+
+// hereRouteDestination finds the last stop of `/directions/<mode>/<stop>/<stop>` routes given as `label:lat,lng`.
+func hereRouteDestination(path string) (*HereLink, bool) {
+	if !strings.HasPrefix(path, "/directions/") {
+		return nil, false
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := len(segments) - 1; i >= 2; i-- {
+		name, location, found := strings.Cut(segments[i], ":")
+		if !found {
+			continue
+		}
+		if latLng, ok := strictLatLng(location, routeStopLatLngPattern); ok {
+			return &HereLink{latLng: latLng, name: name}, true
+		}
+	}
+	return nil, false
+}
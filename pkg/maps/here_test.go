@@ -0,0 +1,71 @@
+package maps
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHereFromURL(t *testing.T) {
+	httpClient := newStubClient(t)
+	oldTownSquare := LatLng{Latitude: 50.08804, Longitude: 14.42076}
+
+	testCases := []struct {
+		name           string
+		inputURL       string
+		expectedLatLng LatLng
+		expectedName   string
+		expectedError  string
+	}{
+		{
+			name:           "Share link",
+			inputURL:       "https://share.here.com/l/50.08804,14.42076,Old%20Town%20Square?z=16&t=normal",
+			expectedLatLng: oldTownSquare,
+			expectedName:   "Old Town Square",
+		},
+		{
+			name:           "Map",
+			inputURL:       "https://wego.here.com/?map=50.08804,14.42076,16,normal",
+			expectedLatLng: oldTownSquare,
+		},
+		{
+			name:           "Directions",
+			inputURL:       "https://wego.here.com/directions/drive/mylocation/Old-Town-Square:50.08804,14.42076?map=50.1,14.4,12,normal",
+			expectedLatLng: oldTownSquare,
+			expectedName:   "Old-Town-Square",
+		},
+		{
+			name:           "Place share link",
+			inputURL:       "https://share.here.com/p/s-Yz1zaWdodHM7aWQ9MjAzbjg0NTktNDYzZTg3YjU",
+			expectedLatLng: oldTownSquare,
+		},
+		{
+			name:          "Unknown share link",
+			inputURL:      "https://share.here.com/p/s-unknown",
+			expectedError: "failed to follow short link",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			link, err := ParseHereFromURL(context.Background(), u, HttpGetToTarget(httpClient))
+
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			latLng, err := link.LatLng()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedLatLng, latLng)
+			assert.Equal(t, tc.expectedName, link.Name())
+		})
+	}
+}
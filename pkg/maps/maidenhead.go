@@ -0,0 +1,126 @@
+package maps
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidMaidenhead is returned for Maidenhead locators not following the field, square and subsquare format.
+var ErrInvalidMaidenhead = errors.New("invalid maidenhead locator")
+
+const (
+	// MaxMaidenheadPairs is the number of pairs down to the extended subsquare, about 400 metres wide.
+	MaxMaidenheadPairs = 5
+	// maidenheadTextRegex matches locators down to at least the subsquare, e.g. `JO81ld`, as shorter ones look like any code,
+	// unless named as such, e.g. `QTH: JO81` or `grid square JO81`.
+	maidenheadTextRegex = `\b([A-R]{2}[0-9]{2}[a-xA-X]{2}(?:[0-9]{2}(?:[a-xA-X]{2})?)?)\b` +
+		`|(?i:\b(?:locator|grid(?:\s+square)?|QTH)(?:\s+is)?\s*[:=]?\s*)([A-R]{2}[0-9]{2})\b`
+)
+
+var maidenheadTextPattern = regexp.MustCompile(maidenheadTextRegex)
+
+// maidenheadPair is a level of the Maidenhead grid, dividing the cell of the previous level into the given number of parts.
+type maidenheadPair struct {
+	first byte
+	parts int
+}
+
+// maidenheadPairs are the field, square, subsquare, extended square and extended subsquare levels.
+var maidenheadPairs = []maidenheadPair{
+	{first: 'A', parts: 18},
+	{first: '0', parts: 10},
+	{first: 'A', parts: 24},
+	{first: '0', parts: 10},
+	{first: 'A', parts: 24},
+}
+
+// Maidenhead is a location given as a Maidenhead grid locator, e.g. `JO81ld`, standing for the center of its cell.
+type Maidenhead string
+
+// LatLng returns the center of the cell of the locator.
+func (m Maidenhead) LatLng() (LatLng, error) {
+	box, err := m.Bounds()
+	if err != nil {
+		return LatLng{}, err
+	}
+	return box.Center(), nil
+}
+
+// Bounds returns the cell of the locator, from the 20°x10° field down to the extended subsquare.
+func (m Maidenhead) Bounds() (BoundingBox, error) {
+	locator := strings.ToUpper(string(m))
+	if len(locator) == 0 || len(locator)%2 == 1 || len(locator) > 2*MaxMaidenheadPairs {
+		return BoundingBox{}, fmt.Errorf("failed to decode %s: %w", m, ErrInvalidMaidenhead)
+	}
+	box := BoundingBox{South: -90, West: -180}
+	lngSize, latSize := 360.0, 180.0
+	for i := 0; i < len(locator); i += 2 {
+		pair := maidenheadPairs[i/2]
+		lngSize /= float64(pair.parts)
+		latSize /= float64(pair.parts)
+		lngDigit, latDigit := int(locator[i])-int(pair.first), int(locator[i+1])-int(pair.first)
+		if lngDigit < 0 || lngDigit >= pair.parts || latDigit < 0 || latDigit >= pair.parts {
+			return BoundingBox{}, fmt.Errorf("failed to decode %s at %d: %w", m, i, ErrInvalidMaidenhead)
+		}
+		box.West += float64(lngDigit) * lngSize
+		box.South += float64(latDigit) * latSize
+	}
+	box.East, box.North = box.West+lngSize, box.South+latSize
+	return box, nil
+}
+
+// EncodeMaidenhead encodes the location into a locator of the given number of pairs, written the usual way,
+// e.g. `JO81ld` with the subsquare in lower case.
+func EncodeMaidenhead(latLng LatLng, pairs int) Maidenhead {
+	if pairs > MaxMaidenheadPairs {
+		pairs = MaxMaidenheadPairs
+	}
+	lng := normalizeLongitude(latLng.Longitude) + 180
+	lat := clipLatitude(latLng.Latitude) + 90
+	lngSize, latSize := 360.0, 180.0
+	var sb strings.Builder
+	for i := 0; i < pairs; i++ {
+		pair := maidenheadPairs[i]
+		lngSize /= float64(pair.parts)
+		latSize /= float64(pair.parts)
+		lngDigit := maidenheadDigit(lng, lngSize, pair.parts)
+		latDigit := maidenheadDigit(lat, latSize, pair.parts)
+		lng -= float64(lngDigit) * lngSize
+		lat -= float64(latDigit) * latSize
+		first := pair.first
+		if i%2 == 0 && i > 0 {
+			first = 'a'
+		}
+		sb.WriteByte(first + byte(lngDigit))
+		sb.WriteByte(first + byte(latDigit))
+	}
+	return Maidenhead(sb.String())
+}
+
+// maidenheadDigit returns the part the value falls in, the north pole belonging to the last one.
+func maidenheadDigit(value, size float64, parts int) int {
+	digit := int(value / size)
+	if digit >= parts {
+		return parts - 1
+	}
+	return digit
+}
+
+// FindMaidenhead finds the first locator given down to at least the subsquare, or the first square named as a locator,
+// in the text, along with the offset it starts at.
+func FindMaidenhead(text string) (Maidenhead, int, bool) {
+	for _, matches := range maidenheadTextPattern.FindAllStringSubmatchIndex(text, -1) {
+		group := 1
+		if matches[2] < 0 {
+			group = 2
+		}
+		locator := Maidenhead(text[matches[2*group]:matches[2*group+1]])
+		if _, err := locator.Bounds(); err == nil {
+			return locator, matches[0], true
+		}
+	}
+	return "", -1, false
+}
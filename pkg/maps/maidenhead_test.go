@@ -0,0 +1,78 @@
+package maps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaidenhead_Bounds(t *testing.T) {
+	testCases := []struct {
+		locator     Maidenhead
+		expectedBox BoundingBox
+		// expectedError is empty for valid locators.
+		expectedError error
+	}{
+		{locator: "JO", expectedBox: BoundingBox{South: 50, West: 0, North: 60, East: 20}},
+		{locator: "JO81", expectedBox: BoundingBox{South: 51, West: 16, North: 52, East: 18}},
+		{locator: "JO81ld", expectedBox: BoundingBox{South: 51 + 3*2.5/60, West: 16 + 11*5.0/60, North: 51 + 4*2.5/60, East: 16 + 12*5.0/60}},
+		{locator: "FN31pr", expectedBox: BoundingBox{South: 41 + 17*2.5/60, West: -74 + 15*5.0/60, North: 41 + 18*2.5/60, East: -74 + 16*5.0/60}},
+		{locator: "fn31PR", expectedBox: BoundingBox{South: 41 + 17*2.5/60, West: -74 + 15*5.0/60, North: 41 + 18*2.5/60, East: -74 + 16*5.0/60}},
+		{locator: "JO81ld42", expectedBox: BoundingBox{South: 51 + 3*2.5/60 + 2*0.25/60, West: 16 + 11*5.0/60 + 4*0.5/60, North: 51 + 3*2.5/60 + 3*0.25/60, East: 16 + 11*5.0/60 + 5*0.5/60}},
+		{locator: "JS81", expectedError: ErrInvalidMaidenhead},
+		{locator: "JO8", expectedError: ErrInvalidMaidenhead},
+		{locator: "JO81lz", expectedError: ErrInvalidMaidenhead},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.locator), func(t *testing.T) {
+			box, err := tc.locator.Bounds()
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expectedBox.South, box.South, 1e-9)
+			assert.InDelta(t, tc.expectedBox.West, box.West, 1e-9)
+			assert.InDelta(t, tc.expectedBox.North, box.North, 1e-9)
+			assert.InDelta(t, tc.expectedBox.East, box.East, 1e-9)
+		})
+	}
+}
+
+func TestEncodeMaidenhead(t *testing.T) {
+	wroclaw := LatLng{Latitude: 51.1069402, Longitude: 17.0772095}
+	assert.Equal(t, Maidenhead("JO81"), EncodeMaidenhead(wroclaw, 2))
+	assert.Equal(t, Maidenhead("JO81mc"), EncodeMaidenhead(wroclaw, 3))
+	assert.Equal(t, Maidenhead("FN31pr"), EncodeMaidenhead(LatLng{Latitude: 41.714775, Longitude: -72.727260}, 3))
+	assert.Equal(t, Maidenhead("RR99xx"), EncodeMaidenhead(LatLng{Latitude: 90, Longitude: 179.9999}, 3))
+
+	for pairs := 1; pairs <= MaxMaidenheadPairs; pairs++ {
+		box, err := EncodeMaidenhead(wroclaw, pairs).Bounds()
+		require.NoError(t, err)
+		assert.True(t, box.South <= wroclaw.Latitude && wroclaw.Latitude < box.North, "pairs %d", pairs)
+		assert.True(t, box.West <= wroclaw.Longitude && wroclaw.Longitude < box.East, "pairs %d", pairs)
+	}
+}
+
+func TestFindMaidenhead(t *testing.T) {
+	locator, start, ok := FindMaidenhead("QTH is JO81ld, 73!")
+	assert.True(t, ok)
+	assert.Equal(t, Maidenhead("JO81ld"), locator)
+	assert.Equal(t, 7, start)
+
+	_, _, ok = FindMaidenhead("see JO81 and ZZ99zz")
+	assert.False(t, ok)
+
+	for text, expectedStart := range map[string]int{"my QTH: JO81, 73": 3, "Grid square JO81": 0, "locator is JO81.": 0} {
+		locator, start, ok = FindMaidenhead(text)
+		assert.True(t, ok, text)
+		assert.Equal(t, Maidenhead("JO81"), locator, text)
+		assert.Equal(t, expectedStart, start, text)
+	}
+
+	_, _, ok = FindMaidenhead("grid ZZ99 and QTH JO8")
+	assert.False(t, ok)
+}
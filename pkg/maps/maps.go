@@ -1,6 +1,7 @@
 package maps
 
 import (
+	"context"
 	"fmt"
 	"github.com/pkg/errors"
 	"io"
@@ -15,6 +16,32 @@ type LatLng struct {
 	Longitude float64
 }
 
+// LatLng makes bare coordinates a Location.
+func (l LatLng) LatLng() (LatLng, error) {
+	return l, nil
+}
+
+// Valid reports whether the latitude and longitude are within their ranges.
+func (l LatLng) Valid() bool {
+	return l.Latitude >= -90 && l.Latitude <= 90 && l.Longitude >= -180 && l.Longitude <= 180
+}
+
+// BoundingBox is the area of a grid cell, e.g. of a geohash or a Maidenhead locator.
+type BoundingBox struct {
+	South, West, North, East float64
+}
+
+// Contains reports whether the location lies within the box.
+func (b BoundingBox) Contains(latLng LatLng) bool {
+	return latLng.Latitude >= b.South && latLng.Latitude <= b.North &&
+		latLng.Longitude >= b.West && latLng.Longitude <= b.East
+}
+
+// Center returns the center of the box.
+func (b BoundingBox) Center() LatLng {
+	return LatLng{Latitude: (b.South + b.North) / 2, Longitude: (b.West + b.East) / 2}
+}
+
 type Location interface {
 	LatLng() (LatLng, error)
 }
@@ -22,65 +49,151 @@ type Location interface {
 const (
 	googleMapsLatLngURLRegex     = `(-?\d+\.\d+),\s*(-?\d+\.\d+)`
 	googleMapsLatLngContentRegex = `@` + googleMapsLatLngURLRegex
+	// googleMapsStaticCenterRegex matches the center of the static map images embedded in place pages.
+	googleMapsStaticCenterRegex = `center=(-?\d+\.\d+)(?:%2C|,)(-?\d+\.\d+)`
+	// googleMapsStateLatLngRegex matches the `[null,null,lat,lng]` arrays of the page initialization state.
+	googleMapsStateLatLngRegex = `\[null,null,(-?\d+\.\d+),(-?\d+\.\d+)\]`
+	googleMapsHost             = "www.google.com"
+	googleMapsSearchPath       = "/maps/search/"
 )
 
 var (
 	latLngURLPattern     = regexp.MustCompile(googleMapsLatLngURLRegex)
 	latLngContentPattern = regexp.MustCompile(googleMapsLatLngContentRegex)
+	// latLngContentPatterns are all tried when extracting from the content of a page, each trusted as much as given.
+	latLngContentPatterns = []struct {
+		pattern    *regexp.Regexp
+		confidence float64
+	}{
+		{latLngContentPattern, confidenceBodyPin},
+		{regexp.MustCompile(googleMapsStaticCenterRegex), confidenceBodyView},
+		{regexp.MustCompile(googleMapsStateLatLngRegex), confidenceBodyState},
+	}
 )
 
 type GoogleMapsLink struct {
-	latLng LatLng
+	// latLng is the location of the best candidate.
+	latLng     LatLng
+	route      *Route
+	name       string
+	candidates []Candidate
+	camera     *Camera
 }
 
 func (l *GoogleMapsLink) LatLng() (LatLng, error) {
 	return l.latLng, nil
 }
 
+// Name returns the name of the place when the link carries one.
+func (l *GoogleMapsLink) Name() string {
+	return l.name
+}
+
+// Route returns the directions route of the link, or nil when the link is not a directions link.
+func (l *GoogleMapsLink) Route() *Route {
+	return l.route
+}
+
+// Camera returns the camera of Street View and Google Earth links, or nil when the link points at a place.
+func (l *GoogleMapsLink) Camera() *Camera {
+	return l.camera
+}
+
+// URL returns the link searching for the location, which opens the app when installed.
+func (l *GoogleMapsLink) URL() *url.URL {
+	return GoogleMapsSearchURL(fmt.Sprintf("%.7f,%.7f", l.latLng.Latitude, l.latLng.Longitude))
+}
+
+// GoogleMapsSearchURL returns the Google Maps search of the query, e.g. of an address or a locality.
+func GoogleMapsSearchURL(query string) *url.URL {
+	q := url.Values{}
+	q.Set("api", "1")
+	q.Set("query", query)
+	return &url.URL{Scheme: "https", Host: googleMapsHost, Path: googleMapsSearchPath, RawQuery: q.Encode()}
+}
+
+// GoogleMapsFromLocation constructs a new Google Maps link from location, keeping the name of named locations.
+func GoogleMapsFromLocation(l Location) (*GoogleMapsLink, error) {
+	latLng, err := l.LatLng()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to extract lat lng from location")
+	}
+	link := &GoogleMapsLink{latLng: latLng}
+	if named, ok := l.(interface{ Name() string }); ok {
+		link.name = named.Name()
+	}
+	return link, nil
+}
+
 // ParseGoogleMapsFromURL extracts GoogleMapsLink from the given URL.
-func ParseGoogleMapsFromURL(u *url.URL, toContent UrlToContent) (*GoogleMapsLink, error) {
-	// First, attempt to extract from URL path.
-	if latLng, err := latLng(u.Path, latLngURLPattern); err == nil {
-		return &GoogleMapsLink{latLng: latLng}, nil
+func ParseGoogleMapsFromURL(ctx context.Context, u *url.URL, toContent UrlToContent) (*GoogleMapsLink, error) {
+	// First, attempt to extract from the URL itself.
+	if link, ok := googleMapsFromURL(u); ok {
+		return link, nil
 	}
 
-	// If not found in URL path, use the toContent function to get alternative content.
-	content, err := toContent(u)
+	// Links to a place by its identifier are followed to the place page.
+	if id, ok := ParsePlaceIdentifier(u); ok {
+		return ResolvePlace(ctx, u, id, toContent)
+	}
+
+	// If not found in the URL, use the toContent function to get alternative content.
+	content, err := toContent(ctx, u)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get content from url: %s, error: %w", u.String(), err)
 	}
 
 	// Attempt to extract from the content.
-	if latLng, err := latLng(content, latLngContentPattern); err == nil {
-		return &GoogleMapsLink{latLng: latLng}, nil
+	if link, ok := googleMapsFromContent(content); ok {
+		link.route, _ = ParseRoute(u)
+		return link, nil
 	}
 
 	return nil, fmt.Errorf("failed to find lat lng for url: %s", u.String())
 }
 
-func latLng(content string, pattern *regexp.Regexp) (LatLng, error) {
+// googleMapsFromContent extracts the location candidates and the place name from the content of a Google Maps page.
+func googleMapsFromContent(content string) (*GoogleMapsLink, bool) {
+	var links []*GoogleMapsLink
+	for _, p := range latLngContentPatterns {
+		if latLng, swapped, err := latLng(content, p.pattern); err == nil {
+			links = append(links, newCandidateLink(newCandidate(latLng, SourceBody, p.confidence, swapped)))
+		}
+	}
+	link, ok := mergeLinks(links)
+	if !ok {
+		return nil, false
+	}
+	link.name = placeName(content)
+	return link, true
+}
+
+// latLng parses the first `lat,lng` match of the pattern, also telling whether the values were swapped.
+func latLng(content string, pattern *regexp.Regexp) (LatLng, bool, error) {
 	matches := pattern.FindStringSubmatch(content)
 	if matches == nil || len(matches) < 3 {
-		return LatLng{}, fmt.Errorf("failed to find latitude and longitude in content")
+		return LatLng{}, false, fmt.Errorf("failed to find latitude and longitude in content")
 	}
 
 	lat, err := parsePointFromString(matches[1]) // Assuming matches[2] is latitude based on corrected indices.
 	if err != nil {
-		return LatLng{}, fmt.Errorf("failed to parse latitude: %w", err)
+		return LatLng{}, false, fmt.Errorf("failed to parse latitude: %w", err)
 	}
 
 	lng, err := parsePointFromString(matches[2]) // Assuming matches[1] is longitude based on corrected indices.
 	if err != nil {
-		return LatLng{}, fmt.Errorf("failed to parse longitude: %w", err)
+		return LatLng{}, false, fmt.Errorf("failed to parse longitude: %w", err)
 	}
 
 	// Check if latitude and longitude might be reversed
+	swapped := false
 	if lat < -90 || lat > 90 {
 		// Swap values if they are reversed
 		lat, lng = lng, lat
+		swapped = true
 	}
 
-	return LatLng{Latitude: lat, Longitude: lng}, nil
+	return LatLng{Latitude: lat, Longitude: lng}, swapped, nil
 }
 
 func parsePointFromString(point string) (float64, error) {
@@ -88,20 +201,31 @@ func parsePointFromString(point string) (float64, error) {
 }
 
 // UrlToContent is a function that takes a URL and returns a string that represents the input to the URL.
-type UrlToContent func(u *url.URL) (string, error)
+// Fetching is abandoned once the context is done.
+type UrlToContent func(ctx context.Context, u *url.URL) (string, error)
 
-func HttpGetToInput(httpClient *http.Client) func(*url.URL) (string, error) {
-	return func(u *url.URL) (string, error) {
-		// Create a new HTTP GET request.
-		req, err := http.NewRequest("GET", u.String(), http.NoBody)
+// HttpGetToInput returns a UrlToContent that downloads the page behind the URL.
+// When the request ends up on the Google consent page, the page from its `continue=` parameter
+// is requested again with the consent cookie and browser-like headers.
+func HttpGetToInput(httpClient *http.Client, opts ...HttpOpt) UrlToContent {
+	o := newHttpOpts(opts...)
+	return func(ctx context.Context, u *url.URL) (string, error) {
+		resp, err := httpGet(ctx, httpClient, u, nil)
 		if err != nil {
-			return "", fmt.Errorf("failed to create request: %w", err)
+			return "", err
 		}
 
-		// Perform the HTTP GET request.
-		resp, err := httpClient.Do(req)
-		if err != nil {
-			return "", fmt.Errorf("failed to request original URL: %s, error: %w", u.String(), err)
+		// Retry the real target of the consent interstitial with the consent cookie.
+		if target, ok := consentTarget(resp); ok {
+			_ = resp.Body.Close()
+			resp, err = httpGet(ctx, httpClient, target, o.withConsent)
+			if err != nil {
+				return "", err
+			}
+			if _, ok := consentTarget(resp); ok {
+				_ = resp.Body.Close()
+				return "", fmt.Errorf("request to URL: %s ended on consent page: %w", u.String(), ErrConsentRequired)
+			}
 		}
 		defer resp.Body.Close()
 
@@ -120,14 +244,58 @@ func HttpGetToInput(httpClient *http.Client) func(*url.URL) (string, error) {
 	}
 }
 
+// UrlToTarget is a function that takes a short link and returns the link it redirects to.
+// Fetching is abandoned once the context is done.
+type UrlToTarget func(ctx context.Context, u *url.URL) (*url.URL, error)
+
+// HttpGetToTarget returns a UrlToTarget that follows the redirects of the link with the client,
+// leaving the body of the final page unread.
+func HttpGetToTarget(httpClient *http.Client) UrlToTarget {
+	return func(ctx context.Context, u *url.URL) (*url.URL, error) {
+		resp, err := httpGet(ctx, httpClient, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("request to URL: %s returned non-OK status: %d", u.String(), resp.StatusCode)
+		}
+		return resp.Request.URL, nil
+	}
+}
+
+// httpGet performs a GET request of the URL, letting decorate adjust the request first.
+func httpGet(ctx context.Context, httpClient *http.Client, u *url.URL, decorate func(*http.Request)) (*http.Response, error) {
+	// Create a new HTTP GET request.
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if decorate != nil {
+		decorate(req)
+	}
+
+	// Perform the HTTP GET request.
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request original URL: %s, error: %w", u.String(), err)
+	}
+	return resp, nil
+}
+
 type WazeLink struct {
-	url *url.URL
+	url    *url.URL
+	latLng LatLng
 }
 
 func (w *WazeLink) URL() *url.URL {
 	return w.url
 }
 
+func (w *WazeLink) LatLng() (LatLng, error) {
+	return w.latLng, nil
+}
+
 const (
 	wazeLinkTemplate = "https://www.waze.com/ul?ll=%s&navigate=yes&zoom=5"
 )
@@ -138,13 +306,17 @@ func WazeFromLocation(l Location) (*WazeLink, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to extract lat lng from location")
 	}
+	return WazeFromLatLng(latLng)
+}
 
+// WazeFromLatLng constructs a new Waze link navigating to latitude & longitude
+func WazeFromLatLng(latLng LatLng) (*WazeLink, error) {
 	geoStr := fmt.Sprintf("%.7f,%.7f", latLng.Latitude, latLng.Longitude)
 	raw := fmt.Sprintf(wazeLinkTemplate, geoStr)
 	u, err := url.Parse(raw)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse url")
 	}
-	w := &WazeLink{url: u}
+	w := &WazeLink{url: u, latLng: latLng}
 	return w, nil
 }
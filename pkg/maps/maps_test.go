@@ -1,6 +1,7 @@
 package maps
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
@@ -40,8 +41,18 @@ func TestParseGoogleMapsFromURL(t *testing.T) {
 			},
 		},
 		{
-			name:     "Valid google maps URL with lat-lng in content",
+			name:     "Valid google maps URL with pin in data param",
 			inputURL: "https://www.google.com/maps/place/Nirvana+Life+Indonesia/@-8.643427,115.1495802,15z/data=!4m20!1m13!4m12!1m4!2m2!1d115.1565824!2d-8.6409216!4e1!1m6!1m2!1s0x2dd239c88caf5ab7:0xc82282485f1666e1!2sNirvana+Life+Indonesia,+Jl.+Tirta+Empul,+Kerobokan,+Kec.+Kuta+Utara,+Kabupaten+Badung,+Bali+80361!2m2!1d115.1646432!2d-8.6455071!3m5!1s0x2dd239c88caf5ab7:0xc82282485f1666e1!8m2!3d-8.6455071!4d115.1646432!16s%2Fg%2F11rq1h0c40?entry=ttu",
+			expectedLink: &GoogleMapsLink{
+				latLng: LatLng{
+					Latitude:  -8.6455071,
+					Longitude: 115.1646432,
+				},
+			},
+		},
+		{
+			name:     "Valid google maps URL with viewport only",
+			inputURL: "https://www.google.com/maps/place/Nirvana+Life+Indonesia/@-8.643427,115.1495802,15z/data=!4m2!3m1!1s0x2dd239c88caf5ab7:0xc82282485f1666e1",
 			expectedLink: &GoogleMapsLink{
 				latLng: LatLng{
 					Latitude:  -8.643427,
@@ -63,7 +74,7 @@ func TestParseGoogleMapsFromURL(t *testing.T) {
 			inputURL, err := url.Parse(tc.inputURL)
 			require.NoError(t, err)
 
-			link, err := ParseGoogleMapsFromURL(inputURL, HttpGetToInput(httpClient))
+			link, err := ParseGoogleMapsFromURL(context.Background(), inputURL, HttpGetToInput(httpClient))
 
 			if tc.expectedError != "" {
 				require.Error(t, err)
@@ -104,13 +115,15 @@ func TestParseGoogleMapsFromURL_Shortened(t *testing.T) {
 	require.NoError(t, err)
 
 	httpClient := &http.Client{}
-	link, err := ParseGoogleMapsFromURL(u, HttpGetToInput(httpClient))
+	link, err := ParseGoogleMapsFromURL(context.Background(), u, HttpGetToInput(httpClient))
 
 	require.NoError(t, err)
+	latLng := LatLng{
+		Latitude:  53.1344674,
+		Longitude: 20.3160387,
+	}
 	assert.Equal(t, link, &GoogleMapsLink{
-		latLng: LatLng{
-			Latitude:  53.1344674,
-			Longitude: 20.3160387,
-		},
+		latLng:     latLng,
+		candidates: []Candidate{{LatLng: latLng, Source: SourceBody, Confidence: confidenceBodyPin}},
 	})
 }
@@ -0,0 +1,50 @@
+package maps
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// MapyCzHosts are the hosts Mapy.cz links are shared from.
+var MapyCzHosts = []string{"mapy.cz", "mapy.com"}
+
+const (
+	// mapyCzShortLinkPath prefixes the short links, e.g. `mapy.cz/s/gapuhemuza`.
+	mapyCzShortLinkPath = "/s/"
+	// mapyCzCoordinatesSource is the `source=` of links to a point given by coordinates in `id=lng,lat`.
+	mapyCzCoordinatesSource = "coor"
+)
+
+// MapyCzLink is a location shared from Mapy.cz.
+type MapyCzLink struct {
+	latLng LatLng
+}
+
+func (l *MapyCzLink) LatLng() (LatLng, error) {
+	return l.latLng, nil
+}
+
+// ParseMapyCzFromURL extracts MapyCzLink from the point of `source=coor&id=lng,lat` links
+// or the `x=lng&y=lat` map center of Mapy.cz links.
+// Short links are followed to the full link with toTarget.
+func ParseMapyCzFromURL(ctx context.Context, u *url.URL, toTarget UrlToTarget) (*MapyCzLink, error) {
+	if strings.HasPrefix(u.Path, mapyCzShortLinkPath) {
+		target, err := toTarget(ctx, u)
+		if err != nil {
+			return nil, fmt.Errorf("failed to follow short link: %s, error: %w", u.String(), err)
+		}
+		u = target
+	}
+	q := u.Query()
+	if q.Get("source") == mapyCzCoordinatesSource {
+		if latLng, _, ok := lngLatLabel(q.Get("id")); ok {
+			return &MapyCzLink{latLng: latLng}, nil
+		}
+	}
+	if latLng, ok := strictLatLng(q.Get("y")+","+q.Get("x"), routeStopLatLngPattern); ok {
+		return &MapyCzLink{latLng: latLng}, nil
+	}
+	return nil, fmt.Errorf("failed to find lat lng for url: %s", u.String())
+}
@@ -0,0 +1,62 @@
+package maps
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMapyCzFromURL(t *testing.T) {
+	httpClient := newStubClient(t)
+	oldTownSquare := LatLng{Latitude: 50.08804, Longitude: 14.42076}
+
+	testCases := []struct {
+		name           string
+		inputURL       string
+		expectedLatLng LatLng
+		expectedError  string
+	}{
+		{
+			name:           "Map center",
+			inputURL:       "https://mapy.cz/zakladni?x=14.4207600&y=50.0880400&z=16",
+			expectedLatLng: oldTownSquare,
+		},
+		{
+			name:           "Point",
+			inputURL:       "https://mapy.cz/turisticka?source=coor&id=14.42076%2C50.08804&x=14.41&y=50.09&z=14",
+			expectedLatLng: oldTownSquare,
+		},
+		{
+			name:           "Short link",
+			inputURL:       "https://mapy.cz/s/gapuhemuza",
+			expectedLatLng: oldTownSquare,
+		},
+		{
+			name:          "Place without coordinates",
+			inputURL:      "https://mapy.cz/zakladni?source=firm&id=12345",
+			expectedError: "failed to find lat lng for url",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			link, err := ParseMapyCzFromURL(context.Background(), u, HttpGetToTarget(httpClient))
+
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			latLng, err := link.LatLng()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedLatLng, latLng)
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package maps
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidMGRS is returned for MGRS references not following the grid zone, square and digits format.
+var ErrInvalidMGRS = errors.New("invalid mgrs reference")
+
+const (
+	// DefaultMGRSPrecision is the number of digits of the easting and the northing, down to the metre.
+	DefaultMGRSPrecision = 5
+	mgrsSquareSize       = 100000.0
+	// mgrsRows are the row letters of the 100 km squares of UTM, repeating every 2000 km.
+	mgrsRows = "ABCDEFGHJKLMNPQRSTUV"
+	// mgrsRegex matches references with or without spaces, e.g. `33U XS 12345 67890`, `33UXS1234567890` or `Z AH 00000 00000`.
+	mgrsRegex = `(?i)\b(\d{1,2}[C-HJ-NP-X]|[ABYZ])\s*([A-HJ-NP-Z][A-HJ-NP-Z])\s*(?:(\d{1,5})\s+(\d{1,5})|(\d{2,10}))?\b`
+)
+
+var mgrsPattern = regexp.MustCompile(mgrsRegex)
+
+// mgrsColumns are the column letters of the 100 km squares of UTM, in sets of eight repeating every three zones.
+var mgrsColumns = [3]string{"STUVWXYZ", "ABCDEFGH", "JKLMNPQR"}
+
+// upsSquares lays out the 100 km squares of a UPS band, which skip more letters than the UTM ones.
+type upsSquares struct {
+	columns  string
+	rows     string
+	easting  float64
+	northing float64
+}
+
+var upsBandSquares = map[byte]upsSquares{
+	'A': {columns: "JKLPQRSTUXYZ", rows: "ABCDEFGHJKLMNPQRSTUVWXYZ", easting: 800000, northing: 800000},
+	'B': {columns: "ABCFGHJKLPQR", rows: "ABCDEFGHJKLMNPQRSTUVWXYZ", easting: 2000000, northing: 800000},
+	'Y': {columns: "JKLPQRSTUXYZ", rows: "ABCDEFGHJKLMNP", easting: 800000, northing: 1300000},
+	'Z': {columns: "ABCFGHJ", rows: "ABCDEFGHJKLMNP", easting: 2000000, northing: 1300000},
+}
+
+// MGRS is a location given as a Military Grid Reference System reference, e.g. `33U XS 12345 67890`,
+// standing for the center of the square its digits narrow it down to.
+type MGRS string
+
+// LatLng returns the center of the square of the reference.
+func (m MGRS) LatLng() (LatLng, error) {
+	u, err := m.UTM()
+	if err != nil {
+		return LatLng{}, err
+	}
+	return u.LatLng()
+}
+
+// UTM returns the UTM coordinates of the center of the square of the reference.
+func (m MGRS) UTM() (UTM, error) {
+	matches := mgrsPattern.FindStringSubmatch(string(m))
+	if matches == nil || matches[0] != strings.TrimSpace(string(m)) {
+		return UTM{}, fmt.Errorf("failed to decode %s: %w", m, ErrInvalidMGRS)
+	}
+	gridZone, square := strings.ToUpper(matches[1]), strings.ToUpper(matches[2])
+	easting, northing := matches[3], matches[4]
+	if digits := matches[5]; digits != "" {
+		if len(digits)%2 == 1 {
+			return UTM{}, fmt.Errorf("failed to split digits of %s: %w", m, ErrInvalidMGRS)
+		}
+		easting, northing = digits[:len(digits)/2], digits[len(digits)/2:]
+	}
+	if len(easting) != len(northing) {
+		return UTM{}, fmt.Errorf("failed to decode digits of %s: %w", m, ErrInvalidMGRS)
+	}
+	// The digits narrow the square down, none of them standing for the whole 100 km square.
+	size := mgrsSquareSize / math.Pow10(len(easting))
+	offsetE, offsetN := size/2, size/2
+	if easting != "" {
+		e, _ := strconv.Atoi(easting)
+		n, _ := strconv.Atoi(northing)
+		offsetE += float64(e) * size
+		offsetN += float64(n) * size
+	}
+
+	band := gridZone[len(gridZone)-1]
+	if len(gridZone) == 1 {
+		squares := upsBandSquares[band]
+		column, row := strings.IndexByte(squares.columns, square[0]), strings.IndexByte(squares.rows, square[1])
+		if column < 0 || row < 0 {
+			return UTM{}, fmt.Errorf("failed to decode square %s of %s: %w", square, m, ErrInvalidMGRS)
+		}
+		return UTM{
+			Band:     band,
+			Easting:  squares.easting + float64(column)*mgrsSquareSize + offsetE,
+			Northing: squares.northing + float64(row)*mgrsSquareSize + offsetN,
+		}, nil
+	}
+
+	zone, _ := strconv.Atoi(gridZone[:len(gridZone)-1])
+	if zone < 1 || zone > 60 {
+		return UTM{}, fmt.Errorf("failed to decode zone of %s: %w", m, ErrInvalidMGRS)
+	}
+	column := strings.IndexByte(mgrsColumns[zone%3], square[0])
+	row := strings.IndexByte(mgrsRows, square[1])
+	if column < 0 || row < 0 {
+		return UTM{}, fmt.Errorf("failed to decode square %s of %s: %w", square, m, ErrInvalidMGRS)
+	}
+	// Rows of even zones start five letters further.
+	if zone%2 == 0 {
+		row = (row - 5 + len(mgrsRows)) % len(mgrsRows)
+	}
+	northing2M := float64(row)*mgrsSquareSize + offsetN
+	// The row letters repeat every 2000 km, the band tells which repetition is meant.
+	bandSouth, err := mgrsBandSouthNorthing(zone, band)
+	if err != nil {
+		return UTM{}, fmt.Errorf("failed to decode band of %s: %w", m, err)
+	}
+	for northing2M < bandSouth {
+		northing2M += 2000000
+	}
+	return UTM{
+		Zone:     zone,
+		Band:     band,
+		Easting:  float64(column+1)*mgrsSquareSize + offsetE,
+		Northing: northing2M,
+	}, nil
+}
+
+// mgrsBandSouthNorthing returns the lowest northing of the band within the zone, rounded down to the square.
+func mgrsBandSouthNorthing(zone int, band byte) (float64, error) {
+	index := strings.IndexByte(utmBands, band)
+	if index < 0 {
+		return 0, ErrInvalidMGRS
+	}
+	lat := utmSouth + 8*float64(index)
+	// Parallels curve towards the pole away from the central meridian, so the lowest northing
+	// is on the meridian in the north and on the edge of the zone in the south.
+	lowest := math.Inf(1)
+	p := utmProjection(zone, lat >= 0)
+	for _, offset := range []float64{0, 3} {
+		_, northing := p.forward(lat, p.originLng+offset)
+		lowest = math.Min(lowest, northing)
+	}
+	return math.Floor(lowest/mgrsSquareSize) * mgrsSquareSize, nil
+}
+
+// EncodeMGRS encodes the location into a reference with the given number of digits of the easting and the northing,
+// e.g. `33U XS 12345 67890` for five digits narrowing the location down to the metre.
+func EncodeMGRS(latLng LatLng, precision int) (MGRS, error) {
+	if precision < 0 || precision > DefaultMGRSPrecision {
+		return "", fmt.Errorf("failed to encode mgrs of precision %d: %w", precision, ErrInvalidMGRS)
+	}
+	u, err := UTMFromLatLng(latLng)
+	if err != nil {
+		return "", err
+	}
+	column, row := int(u.Easting/mgrsSquareSize), int(u.Northing/mgrsSquareSize)
+	var gridZone, square string
+	if u.Zone == 0 {
+		squares := upsBandSquares[u.Band]
+		column -= int(squares.easting / mgrsSquareSize)
+		row -= int(squares.northing / mgrsSquareSize)
+		gridZone = string(u.Band)
+		square = string([]byte{squares.columns[column], squares.rows[row]})
+	} else {
+		if u.Zone%2 == 0 {
+			row += 5
+		}
+		gridZone = fmt.Sprintf("%d%c", u.Zone, u.Band)
+		square = string([]byte{mgrsColumns[u.Zone%3][column-1], mgrsRows[row%len(mgrsRows)]})
+	}
+	if precision == 0 {
+		return MGRS(gridZone + " " + square), nil
+	}
+	// Digits are truncated rather than rounded, so that the reference names the square the location lies in.
+	size := mgrsSquareSize / math.Pow10(precision)
+	easting := int(math.Mod(u.Easting, mgrsSquareSize) / size)
+	northing := int(math.Mod(u.Northing, mgrsSquareSize) / size)
+	return MGRS(fmt.Sprintf("%s %s %0*d %0*d", gridZone, square, precision, easting, precision, northing)), nil
+}
+
+// FindMGRS finds the first valid MGRS reference in the text, e.g. `33U XS 12345 67890`, along with the offset it starts at.
+func FindMGRS(text string) (MGRS, int, bool) {
+	for _, matches := range mgrsPattern.FindAllStringSubmatchIndex(text, -1) {
+		// References without digits look like any word, only ones narrowed down are taken.
+		if matches[6] < 0 && matches[10] < 0 {
+			continue
+		}
+		m := MGRS(text[matches[0]:matches[1]])
+		if _, err := m.UTM(); err == nil {
+			return m, matches[0], true
+		}
+	}
+	return "", -1, false
+}
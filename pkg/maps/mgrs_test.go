@@ -0,0 +1,84 @@
+package maps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeMGRS(t *testing.T) {
+	testCases := []struct {
+		name         string
+		latLng       LatLng
+		precision    int
+		expectedMGRS MGRS
+	}{
+		{name: "Equator on the prime meridian", latLng: LatLng{Latitude: 0, Longitude: 0}, precision: 5, expectedMGRS: "31N AA 66021 00000"},
+		{name: "Eiffel Tower", latLng: LatLng{Latitude: 48.8582, Longitude: 2.2945}, precision: 5, expectedMGRS: "31U DQ 48251 11932"},
+		{name: "Even zone", latLng: LatLng{Latitude: -33.8568, Longitude: 151.2153}, precision: 5, expectedMGRS: "56H LH 34900 52288"},
+		{name: "Hundred metres", latLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095}, precision: 3, expectedMGRS: "33U XS 454 637"},
+		{name: "Square only", latLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095}, precision: 0, expectedMGRS: "33U XS"},
+		{name: "Norway exception", latLng: LatLng{Latitude: 60, Longitude: 4}, precision: 5, expectedMGRS: "32V KM 21288 61953"},
+		{name: "Svalbard exception", latLng: LatLng{Latitude: 78, Longitude: 10}, precision: 5, expectedMGRS: "33X UG 84085 63320"},
+		{name: "North pole", latLng: LatLng{Latitude: 90, Longitude: 0}, precision: 5, expectedMGRS: "Z AH 00000 00000"},
+		{name: "South pole", latLng: LatLng{Latitude: -90, Longitude: 0}, precision: 5, expectedMGRS: "B AN 00000 00000"},
+		{name: "Arctic west of Greenwich", latLng: LatLng{Latitude: 85, Longitude: -20}, precision: 5, expectedMGRS: "Y YB 10022 78040"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := EncodeMGRS(tc.latLng, tc.precision)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMGRS, m)
+		})
+	}
+}
+
+func TestMGRS_LatLng(t *testing.T) {
+	testCases := []struct {
+		mgrs           MGRS
+		expectedLatLng LatLng
+		// expectedDelta is about the size of the square the reference narrows the location down to.
+		expectedDelta float64
+		expectedError error
+	}{
+		{mgrs: "33U XS 45414 63769", expectedLatLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095}, expectedDelta: 1e-5},
+		{mgrs: "33uxs4541463769", expectedLatLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095}, expectedDelta: 1e-5},
+		{mgrs: "33U XS 454 637", expectedLatLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095}, expectedDelta: 1e-3},
+		{mgrs: "56H LH 34900 52288", expectedLatLng: LatLng{Latitude: -33.8568, Longitude: 151.2153}, expectedDelta: 1e-5},
+		{mgrs: "31N AA 66021 00000", expectedLatLng: LatLng{Latitude: 0, Longitude: 0}, expectedDelta: 1e-5},
+		{mgrs: "33X UG 84085 63320", expectedLatLng: LatLng{Latitude: 78, Longitude: 10}, expectedDelta: 1e-4},
+		{mgrs: "Y YB 10022 78040", expectedLatLng: LatLng{Latitude: 85, Longitude: -20}, expectedDelta: 1e-4},
+		{mgrs: "B HM 47018 03545", expectedLatLng: LatLng{Latitude: -85, Longitude: 100}, expectedDelta: 1e-4},
+		{mgrs: "33U XS 1234 567", expectedError: ErrInvalidMGRS},
+		{mgrs: "33U XW 12345 67890", expectedError: ErrInvalidMGRS},
+		{mgrs: "61U XS 12345 67890", expectedError: ErrInvalidMGRS},
+		{mgrs: "Z XA 12345 67890", expectedError: ErrInvalidMGRS},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.mgrs), func(t *testing.T) {
+			latLng, err := tc.mgrs.LatLng()
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expectedLatLng.Latitude, latLng.Latitude, tc.expectedDelta)
+			assert.InDelta(t, tc.expectedLatLng.Longitude, latLng.Longitude, tc.expectedDelta)
+		})
+	}
+}
+
+func TestFindMGRS(t *testing.T) {
+	m, start, ok := FindMGRS("Team 2 at 33U XS 12345 67890, over")
+	assert.True(t, ok)
+	assert.Equal(t, MGRS("33U XS 12345 67890"), m)
+	assert.Equal(t, 10, start)
+
+	_, _, ok = FindMGRS("a bus is due at 33U")
+	assert.False(t, ok)
+}
@@ -0,0 +1,200 @@
+package maps
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// OrganicMapsHosts are the hosts Organic Maps and Maps.me links are shared from, next to the `ge0://` scheme.
+var OrganicMapsHosts = []string{"omaps.app", "ge0.me"}
+
+const (
+	// Ge0Scheme is the scheme of the links opened directly in Organic Maps, e.g. `ge0://8wAAAAAAAA/Name`.
+	Ge0Scheme = "ge0"
+	// ge0Alphabet maps 6-bit digits of a ge0 code to characters.
+	ge0Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	// ge0LatLngLength is the number of characters encoding the location, each carrying 3 bits of both coordinates.
+	ge0LatLngLength = 9
+	// ge0CoordBits is the precision the coordinates are scaled to, of which the encoded characters keep the top bits.
+	ge0CoordBits = 30
+	ge0MaxCoord  = 1<<ge0CoordBits - 1
+	// ge0MaxNameLength is the most bytes of a decoded name kept.
+	ge0MaxNameLength = 256
+	organicMapsHost  = "omaps.app"
+	// defaultOrganicMapsZoom is the zoom of generated links.
+	defaultOrganicMapsZoom = 17
+	// Zoom levels are encoded in quarters from minGe0Zoom up to maxGe0Zoom.
+	minGe0Zoom = 4
+	maxGe0Zoom = 19.75
+)
+
+// OrganicMapsLink is a location shared from Organic Maps or Maps.me, packing the zoom, the coordinates and the name.
+type OrganicMapsLink struct {
+	latLng LatLng
+	zoom   float64
+	name   string
+}
+
+func (l *OrganicMapsLink) LatLng() (LatLng, error) {
+	return l.latLng, nil
+}
+
+// Name returns the name of the place when the link carries one.
+func (l *OrganicMapsLink) Name() string {
+	return l.name
+}
+
+// Zoom returns the zoom level of the map, in quarters of a level.
+func (l *OrganicMapsLink) Zoom() float64 {
+	return l.zoom
+}
+
+// URL returns the `https://omaps.app/<code>/<name>` link, which opens in a browser when the app is missing.
+func (l *OrganicMapsLink) URL() *url.URL {
+	return l.url("https", organicMapsHost, "/"+EncodeGe0(l.latLng, l.zoom))
+}
+
+// Ge0URL returns the `ge0://<code>/<name>` link, which opens in the app directly.
+func (l *OrganicMapsLink) Ge0URL() *url.URL {
+	return l.url(Ge0Scheme, EncodeGe0(l.latLng, l.zoom), "")
+}
+
+func (l *OrganicMapsLink) url(scheme, host, path string) *url.URL {
+	u := &url.URL{Scheme: scheme, Host: host, Path: path}
+	if l.name != "" {
+		encoded := encodeGe0Name(l.name)
+		decoded, _ := url.PathUnescape(encoded)
+		u.Path, u.RawPath = path+"/"+decoded, path+"/"+encoded
+	}
+	return u
+}
+
+// OrganicMapsFromLocation constructs a new Organic Maps link from location, keeping the name of named locations.
+func OrganicMapsFromLocation(l Location) (*OrganicMapsLink, error) {
+	latLng, err := l.LatLng()
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract lat lng from location: %w", err)
+	}
+	link := &OrganicMapsLink{latLng: latLng, zoom: defaultOrganicMapsZoom}
+	if named, ok := l.(interface{ Name() string }); ok {
+		link.name = named.Name()
+	}
+	return link, nil
+}
+
+// ParseOrganicMapsFromURL extracts OrganicMapsLink from `ge0://<code>/<name>` and `https://omaps.app/<code>/<name>` links.
+func ParseOrganicMapsFromURL(u *url.URL) (*OrganicMapsLink, error) {
+	raw := strings.TrimPrefix(u.EscapedPath(), "/")
+	if u.Scheme == Ge0Scheme {
+		raw = u.Host + u.EscapedPath()
+	}
+	code, name, _ := strings.Cut(raw, "/")
+	latLng, zoom, err := DecodeGe0(code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode ge0 code of url: %s, error: %w", u.String(), err)
+	}
+	link := &OrganicMapsLink{latLng: latLng, zoom: zoom}
+	if link.name, err = decodeGe0Name(name); err != nil {
+		return nil, fmt.Errorf("failed to decode name of url: %s, error: %w", u.String(), err)
+	}
+	// The name is cut once decoded, so that neither an escape nor a character is split.
+	if len(link.name) > ge0MaxNameLength {
+		end := ge0MaxNameLength
+		for end > 0 && !utf8.RuneStart(link.name[end]) {
+			end--
+		}
+		link.name = link.name[:end]
+	}
+	return link, nil
+}
+
+// EncodeGe0 encodes the zoom into the first character of the code, followed by the coordinates.
+// Both coordinates are scaled to 30 bits and interleaved 3 bits per character, latitude first,
+// the 9 characters keeping the top 27 bits of each.
+func EncodeGe0(latLng LatLng, zoom float64) string {
+	var sb strings.Builder
+	zoomDigit := 0
+	switch {
+	case zoom >= maxGe0Zoom:
+		zoomDigit = 63
+	case zoom > minGe0Zoom:
+		zoomDigit = int((zoom - minGe0Zoom) * 4)
+	}
+	sb.WriteByte(ge0Alphabet[zoomDigit])
+
+	lat := ge0Lat(latLng.Latitude)
+	lng := ge0Lng(latLng.Longitude)
+	for i, shift := 0, ge0CoordBits-3; i < ge0LatLngLength; i, shift = i+1, shift-3 {
+		latBits, lngBits := lat>>shift&7, lng>>shift&7
+		digit := (latBits>>2&1)<<5 | (lngBits>>2&1)<<4 | (latBits>>1&1)<<3 | (lngBits>>1&1)<<2 | (latBits&1)<<1 | lngBits&1
+		sb.WriteByte(ge0Alphabet[digit])
+	}
+	return sb.String()
+}
+
+// DecodeGe0 decodes the location and zoom of a ge0 code. The location is the center of the cell the code stands for.
+func DecodeGe0(code string) (LatLng, float64, error) {
+	if len(code) != 1+ge0LatLngLength {
+		return LatLng{}, 0, fmt.Errorf("invalid ge0 code length: %d", len(code))
+	}
+	zoomDigit := strings.IndexByte(ge0Alphabet, code[0])
+	if zoomDigit < 0 {
+		return LatLng{}, 0, fmt.Errorf("invalid ge0 character %q at 0", code[0])
+	}
+	var lat, lng int
+	for i, shift := 1, ge0CoordBits-3; i < len(code); i, shift = i+1, shift-3 {
+		digit := strings.IndexByte(ge0Alphabet, code[i])
+		if digit < 0 {
+			return LatLng{}, 0, fmt.Errorf("invalid ge0 character %q at %d", code[i], i)
+		}
+		lat |= (digit>>5&1<<2 | digit>>3&1<<1 | digit>>1&1) << shift
+		lng |= (digit>>4&1<<2 | digit>>2&1<<1 | digit&1) << shift
+	}
+	// Move from the corner to the middle of the cell, whose side is the lowest bits left out of the code.
+	middle := 1 << (ge0CoordBits - 3*ge0LatLngLength - 1)
+	lat += middle
+	lng += middle
+	latLng := LatLng{
+		Latitude:  float64(lat)/ge0MaxCoord*180 - 90,
+		Longitude: float64(lng)/(ge0MaxCoord+1)*360 - 180,
+	}
+	return latLng, float64(zoomDigit)/4 + minGe0Zoom, nil
+}
+
+func ge0Lat(lat float64) int {
+	x := (lat + 90) / 180 * ge0MaxCoord
+	switch {
+	case x < 0:
+		return 0
+	case x > ge0MaxCoord:
+		return ge0MaxCoord
+	default:
+		return int(x + 0.5)
+	}
+}
+
+func ge0Lng(lng float64) int {
+	// Bring the longitude within [-180, 180) first.
+	lng = math.Mod(math.Mod(lng+180, 360)+360, 360) - 180
+	x := (lng+180)/360*(ge0MaxCoord+1) + 0.5
+	if x <= 0 || x >= ge0MaxCoord+1 {
+		return 0
+	}
+	return int(x)
+}
+
+// encodeGe0Name writes spaces as `_`, escaping the underscores of the name itself.
+func encodeGe0Name(name string) string {
+	parts := strings.Split(name, "_")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(strings.ReplaceAll(part, " ", "_"))
+	}
+	return strings.Join(parts, "%5F")
+}
+
+func decodeGe0Name(raw string) (string, error) {
+	return url.PathUnescape(strings.ReplaceAll(raw, "_", " "))
+}
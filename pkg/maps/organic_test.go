@@ -0,0 +1,125 @@
+package maps
+
+import (
+	"math/rand"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ge0CellDegrees is the side of the cell of a ge0 code in degrees of latitude, 27 bits per coordinate.
+const ge0CellDegrees = 180.0 / (1 << (3 * ge0LatLngLength))
+
+func TestParseOrganicMapsFromURL(t *testing.T) {
+	testCases := []struct {
+		name           string
+		inputURL       string
+		expectedLatLng LatLng
+		expectedZoom   float64
+		expectedName   string
+		expectedError  string
+	}{
+		{
+			name:           "Ge0 scheme",
+			inputURL:       "ge0://8wAAAAAAAA/Name",
+			expectedLatLng: LatLng{Latitude: 0, Longitude: 0},
+			expectedZoom:   19,
+			expectedName:   "Name",
+		},
+		{
+			name:           "Omaps app with name",
+			inputURL:       "https://omaps.app/w4NCMunrVR/Hala_Stulecia",
+			expectedLatLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095},
+			expectedZoom:   16,
+			expectedName:   "Hala Stulecia",
+		},
+		{
+			name:           "Escaped underscore in name",
+			inputURL:       "https://omaps.app/8wAAAAAAAA/Null_%5F_Island",
+			expectedLatLng: LatLng{Latitude: 0, Longitude: 0},
+			expectedZoom:   19,
+			expectedName:   "Null _ Island",
+		},
+		{
+			name:           "Without name",
+			inputURL:       "https://ge0.me/8wAAAAAAAA",
+			expectedLatLng: LatLng{Latitude: 0, Longitude: 0},
+			expectedZoom:   19,
+		},
+		{
+			name:           "Long name cut at a character",
+			inputURL:       "https://omaps.app/8wAAAAAAAA/a" + strings.Repeat("%C5%82", 200),
+			expectedLatLng: LatLng{Latitude: 0, Longitude: 0},
+			expectedZoom:   19,
+			expectedName:   "a" + strings.Repeat("ł", 127),
+		},
+		{
+			name:          "Too short code",
+			inputURL:      "https://omaps.app/8wAAAA/Name",
+			expectedError: "invalid ge0 code length: 6",
+		},
+		{
+			name:          "Invalid character",
+			inputURL:      "ge0://8wAA.AAAAA/Name",
+			expectedError: "invalid ge0 character '.' at 4",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			link, err := ParseOrganicMapsFromURL(u)
+
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			latLng, err := link.LatLng()
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expectedLatLng.Latitude, latLng.Latitude, ge0CellDegrees)
+			assert.InDelta(t, tc.expectedLatLng.Longitude, latLng.Longitude, 2*ge0CellDegrees)
+			assert.Equal(t, tc.expectedZoom, link.Zoom())
+			assert.Equal(t, tc.expectedName, link.Name())
+		})
+	}
+}
+
+func TestOrganicMapsFromLocation(t *testing.T) {
+	location := &AppleMapsLink{latLng: LatLng{Latitude: 0, Longitude: 0}, name: "Null_Island Café"}
+
+	link, err := OrganicMapsFromLocation(location)
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://omaps.app/0wAAAAAAAA/Null%5FIsland_Caf%C3%A9", link.URL().String())
+	assert.Equal(t, "ge0://0wAAAAAAAA/Null%5FIsland_Caf%C3%A9", link.Ge0URL().String())
+
+	parsed, err := ParseOrganicMapsFromURL(link.URL())
+	require.NoError(t, err)
+	assert.Equal(t, "Null_Island Café", parsed.Name())
+}
+
+func TestGe0_RoundTrip(t *testing.T) {
+	assert.Equal(t, "8wAAAAAAAA", EncodeGe0(LatLng{Latitude: 0, Longitude: 0}, 19))
+	assert.Equal(t, "AwAAAAAAAA", EncodeGe0(LatLng{Latitude: 0, Longitude: 0}, 2))
+	assert.Equal(t, "_wAAAAAAAA", EncodeGe0(LatLng{Latitude: 0, Longitude: 0}, 21))
+
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		latLng := LatLng{Latitude: random.Float64()*180 - 90, Longitude: random.Float64()*360 - 180}
+		zoom := minGe0Zoom + float64(random.Intn(64))/4
+
+		decoded, decodedZoom, err := DecodeGe0(EncodeGe0(latLng, zoom))
+
+		require.NoError(t, err)
+		require.Equal(t, zoom, decodedZoom)
+		require.InDelta(t, latLng.Latitude, decoded.Latitude, ge0CellDegrees)
+		require.InDelta(t, latLng.Longitude, decoded.Longitude, 2*ge0CellDegrees)
+	}
+}
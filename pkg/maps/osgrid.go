@@ -0,0 +1,129 @@
+package maps
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidOSGridRef is returned for grid references outside of the British National Grid.
+var ErrInvalidOSGridRef = errors.New("invalid os grid reference")
+
+const (
+	// DefaultOSGridRefPrecision is the number of digits of the easting and the northing, down to the metre.
+	DefaultOSGridRefPrecision = 5
+	osGridSquareSize          = 100000.0
+	// osGridRefRegex matches references down to at least 10 km with or without spaces, e.g. `TQ 30080 80001` or `TQ3008080001`.
+	osGridRefRegex = `\b([HJNOST][A-HJ-Z])\s*(?:(\d{2,5})\s+(\d{2,5})|(\d{4,10}))\b`
+	// osGridRefTextRegex matches references down to at least 10 m in text, as shorter ones look like room or flight numbers.
+	osGridRefTextRegex = `\b[HJNOST][A-HJ-Z]\s*(?:\d{4,5}\s+\d{4,5}|\d{8,10})\b`
+)
+
+var (
+	osGridRefPattern     = regexp.MustCompile(osGridRefRegex)
+	osGridRefTextPattern = regexp.MustCompile(osGridRefTextRegex)
+)
+
+// britishNationalGrid is the Ordnance Survey National Grid, a transverse Mercator projection of the OSGB36 datum.
+var britishNationalGrid = crs{
+	datum: osgb36Datum,
+	projection: transverseMercator{
+		ellipsoid:     airy1830,
+		scale:         0.9996012717,
+		originLat:     49,
+		originLng:     -2,
+		falseEasting:  400000,
+		falseNorthing: -100000,
+	},
+}
+
+// OSGridRef is a location given as an Ordnance Survey grid reference, e.g. `TQ 30080 80001`,
+// standing for the center of the square its digits narrow it down to.
+type OSGridRef string
+
+// LatLng returns the WGS84 location of the center of the square of the reference.
+func (r OSGridRef) LatLng() (LatLng, error) {
+	easting, northing, err := r.EastingNorthing()
+	if err != nil {
+		return LatLng{}, err
+	}
+	return britishNationalGrid.latLng(easting, northing), nil
+}
+
+// EastingNorthing returns the all-numeric grid coordinates of the center of the square of the reference.
+func (r OSGridRef) EastingNorthing() (easting, northing float64, err error) {
+	matches := osGridRefPattern.FindStringSubmatch(string(r))
+	if matches == nil || matches[0] != strings.TrimSpace(string(r)) {
+		return 0, 0, fmt.Errorf("failed to decode %s: %w", r, ErrInvalidOSGridRef)
+	}
+	eDigits, nDigits := matches[2], matches[3]
+	if digits := matches[4]; digits != "" {
+		if len(digits)%2 == 1 {
+			return 0, 0, fmt.Errorf("failed to split digits of %s: %w", r, ErrInvalidOSGridRef)
+		}
+		eDigits, nDigits = digits[:len(digits)/2], digits[len(digits)/2:]
+	}
+	if len(eDigits) != len(nDigits) {
+		return 0, 0, fmt.Errorf("failed to decode digits of %s: %w", r, ErrInvalidOSGridRef)
+	}
+	// The first letter picks a 500 km square of a 5x5 grid and the second one a 100 km square within it,
+	// both skipping I, with the false origin at the south-west corner of square S.
+	l1, l2 := int(matches[1][0]-'A'), int(matches[1][1]-'A')
+	if l1 > 7 {
+		l1--
+	}
+	if l2 > 7 {
+		l2--
+	}
+	column := ((l1-2)%5)*5 + l2%5
+	row := (19 - (l1/5)*5) - l2/5
+	if column < 0 || column > 6 || row < 0 || row > 12 {
+		return 0, 0, fmt.Errorf("failed to decode square of %s: %w", r, ErrInvalidOSGridRef)
+	}
+	size := osGridSquareSize / math.Pow10(len(eDigits))
+	e, _ := strconv.Atoi(eDigits)
+	n, _ := strconv.Atoi(nDigits)
+	easting = float64(column)*osGridSquareSize + (float64(e)+0.5)*size
+	northing = float64(row)*osGridSquareSize + (float64(n)+0.5)*size
+	return easting, northing, nil
+}
+
+// EncodeOSGridRef encodes the location into a reference with the given number of digits of the easting and the northing,
+// e.g. `TQ 30080 80001` for five digits narrowing the location down to the metre.
+func EncodeOSGridRef(latLng LatLng, precision int) (OSGridRef, error) {
+	if precision < 1 || precision > DefaultOSGridRefPrecision {
+		return "", fmt.Errorf("failed to encode os grid reference of precision %d: %w", precision, ErrInvalidOSGridRef)
+	}
+	easting, northing := britishNationalGrid.grid(latLng)
+	column, row := int(math.Floor(easting/osGridSquareSize)), int(math.Floor(northing/osGridSquareSize))
+	if column < 0 || column > 6 || row < 0 || row > 12 {
+		return "", fmt.Errorf("failed to encode location outside of the grid: %v: %w", latLng, ErrInvalidOSGridRef)
+	}
+	l1 := (19 - row) - (19-row)%5 + (column+10)/5
+	l2 := (19-row)*5%25 + column%5
+	if l1 > 7 {
+		l1++
+	}
+	if l2 > 7 {
+		l2++
+	}
+	size := osGridSquareSize / math.Pow10(precision)
+	e := int(math.Mod(easting, osGridSquareSize) / size)
+	n := int(math.Mod(northing, osGridSquareSize) / size)
+	return OSGridRef(fmt.Sprintf("%c%c %0*d %0*d", 'A'+l1, 'A'+l2, precision, e, precision, n)), nil
+}
+
+// FindOSGridRef finds the first valid grid reference in the text, e.g. `TQ 30080 80001`, along with the offset it starts at.
+func FindOSGridRef(text string) (OSGridRef, int, bool) {
+	for _, match := range osGridRefTextPattern.FindAllStringIndex(text, -1) {
+		ref := OSGridRef(text[match[0]:match[1]])
+		if _, _, err := ref.EastingNorthing(); err == nil {
+			return ref, match[0], true
+		}
+	}
+	return "", -1, false
+}
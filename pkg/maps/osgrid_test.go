@@ -0,0 +1,86 @@
+package maps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOSGridRef_EastingNorthing(t *testing.T) {
+	testCases := []struct {
+		ref              OSGridRef
+		expectedEasting  float64
+		expectedNorthing float64
+		expectedError    error
+	}{
+		{ref: "TG 51409 13177", expectedEasting: 651409.5, expectedNorthing: 313177.5},
+		{ref: "TG5140913177", expectedEasting: 651409.5, expectedNorthing: 313177.5},
+		{ref: "TQ 300 800", expectedEasting: 530050, expectedNorthing: 180050},
+		{ref: "SV 00000 00000", expectedEasting: 0.5, expectedNorthing: 0.5},
+		{ref: "HP 40000 12345", expectedEasting: 440000.5, expectedNorthing: 1212345.5},
+		{ref: "NN 166 712", expectedEasting: 216650, expectedNorthing: 771250},
+		{ref: "TQ 3008 800", expectedError: ErrInvalidOSGridRef},
+		{ref: "TQ 3 8", expectedError: ErrInvalidOSGridRef},
+		{ref: "HA 12345 12345", expectedError: ErrInvalidOSGridRef},
+		{ref: "TQ 30080 80001 extra", expectedError: ErrInvalidOSGridRef},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.ref), func(t *testing.T) {
+			easting, northing, err := tc.ref.EastingNorthing()
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedEasting, easting)
+			assert.Equal(t, tc.expectedNorthing, northing)
+		})
+	}
+}
+
+func TestOSGridRef_LatLng(t *testing.T) {
+	// Caister water tower of the Ordnance Survey guide, within the 5 metre accuracy of the Helmert transformation.
+	latLng, err := OSGridRef("TG 51409 13177").LatLng()
+
+	require.NoError(t, err)
+	assert.InDelta(t, dms(52, 39, 28.72), latLng.Latitude, 5e-5)
+	assert.InDelta(t, dms(1, 42, 57.79), latLng.Longitude, 5e-5)
+}
+
+func TestEncodeOSGridRef(t *testing.T) {
+	caister := LatLng{Latitude: dms(52, 39, 28.72), Longitude: dms(1, 42, 57.79)}
+
+	ref, err := EncodeOSGridRef(caister, 3)
+	require.NoError(t, err)
+	assert.Equal(t, OSGridRef("TG 514 131"), ref)
+
+	ben, err := EncodeOSGridRef(LatLng{Latitude: 56.796891, Longitude: -5.003675}, 5)
+	require.NoError(t, err)
+	latLng, err := ben.LatLng()
+	require.NoError(t, err)
+	assert.InDelta(t, 56.796891, latLng.Latitude, 1e-5)
+	assert.InDelta(t, -5.003675, latLng.Longitude, 1e-5)
+
+	_, err = EncodeOSGridRef(LatLng{Latitude: 51.1069402, Longitude: 17.0772095}, 5)
+	assert.ErrorIs(t, err, ErrInvalidOSGridRef)
+}
+
+func TestFindOSGridRef(t *testing.T) {
+	ref, start, ok := FindOSGridRef("Meet at TQ 30080 80001 at noon")
+	assert.True(t, ok)
+	assert.Equal(t, OSGridRef("TQ 30080 80001"), ref)
+	assert.Equal(t, 8, start)
+
+	ref, _, ok = FindOSGridRef("Summit NN16637125")
+	assert.True(t, ok)
+	assert.Equal(t, OSGridRef("NN16637125"), ref)
+
+	_, _, ok = FindOSGridRef("Room SA 12, flight TA 1")
+	assert.False(t, ok)
+
+	_, _, ok = FindOSGridRef("Gate SH 12 34, room TL 102 205")
+	assert.False(t, ok)
+}
@@ -0,0 +1,173 @@
+package maps
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// OSMHosts are the hosts OpenStreetMap links are shared from, osm.org serving the `/go/` shortlinks.
+var OSMHosts = []string{"openstreetmap.org", "osm.org"}
+
+const (
+	// osmShortLinkAlphabet maps 6-bit digits of a shortlink code to characters. Old codes use `@` instead of `~`.
+	osmShortLinkAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789_~"
+	// osmShortLinkZoomPad is the number of zoom levels added to the zoom, approximating one pixel of a tile.
+	osmShortLinkZoomPad = 8
+	osmShortLinkPrefix  = "/go/"
+	osmMapFragmentKey   = "map"
+	osmDefaultZoom      = 16
+)
+
+// OSMLink is a location shared from OpenStreetMap.
+type OSMLink struct {
+	latLng LatLng
+	zoom   int
+	// marker tells whether the link places a marker at the location rather than just centering the map on it.
+	marker bool
+}
+
+func (l *OSMLink) LatLng() (LatLng, error) {
+	return l.latLng, nil
+}
+
+// Zoom returns the zoom level of the map.
+func (l *OSMLink) Zoom() int {
+	return l.zoom
+}
+
+// Marker tells whether the link places a marker at the location.
+func (l *OSMLink) Marker() bool {
+	return l.marker
+}
+
+// ParseOSMFromURL extracts OSMLink from the `?mlat=&mlon=` marker, the `osm.org/go/<code>` shortlink,
+// the `#map=zoom/lat/lon` view or the legacy `?lat=&lon=` view of an OpenStreetMap link, in that order.
+// Links to `/node/<id>` and other objects are accepted when they carry a marker or a view.
+func ParseOSMFromURL(u *url.URL) (*OSMLink, error) {
+	q := u.Query()
+	zoom, view, hasView := osmMapFragment(u.Fragment)
+	if !hasView {
+		zoom = osmDefaultZoom
+	}
+	if latLng, ok := osmQueryLatLng(q, "mlat", "mlon"); ok {
+		return &OSMLink{latLng: latLng, zoom: zoom, marker: true}, nil
+	}
+	if strings.HasPrefix(u.Path, osmShortLinkPrefix) {
+		latLng, zoom, err := DecodeOSMShortLink(strings.TrimPrefix(u.Path, osmShortLinkPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode shortlink: %s, error: %w", u.String(), err)
+		}
+		_, marker := q["m"]
+		return &OSMLink{latLng: latLng, zoom: zoom, marker: marker}, nil
+	}
+	if hasView {
+		return &OSMLink{latLng: view, zoom: zoom}, nil
+	}
+	if latLng, ok := osmQueryLatLng(q, "lat", "lon"); ok {
+		if z, err := strconv.Atoi(q.Get("zoom")); err == nil {
+			zoom = z
+		}
+		return &OSMLink{latLng: latLng, zoom: zoom}, nil
+	}
+	return nil, fmt.Errorf("failed to find lat lng for url: %s", u.String())
+}
+
+// osmMapFragment parses the `map=zoom/lat/lon` entry of the fragment, e.g. `#map=17/51.10694/17.07721&layers=C`.
+func osmMapFragment(fragment string) (int, LatLng, bool) {
+	values, err := url.ParseQuery(fragment)
+	if err != nil {
+		return 0, LatLng{}, false
+	}
+	parts := strings.Split(values.Get(osmMapFragmentKey), "/")
+	if len(parts) != 3 {
+		return 0, LatLng{}, false
+	}
+	zoom, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, LatLng{}, false
+	}
+	latLng, ok := strictLatLng(parts[1]+","+parts[2], routeStopLatLngPattern)
+	if !ok {
+		return 0, LatLng{}, false
+	}
+	return zoom, latLng, true
+}
+
+func osmQueryLatLng(q url.Values, latName, lngName string) (LatLng, bool) {
+	if q.Get(latName) == "" || q.Get(lngName) == "" {
+		return LatLng{}, false
+	}
+	return strictLatLng(q.Get(latName)+","+q.Get(lngName), routeStopLatLngPattern)
+}
+
+// EncodeOSMShortLink encodes the location and zoom into the code of an `osm.org/go/<code>` shortlink.
+// Longitude and latitude are scaled to 32 bits each and interleaved, longitude first, then written
+// 6 bits per character, one character per 3 zoom levels. Trailing `-` stand for partial zoom levels.
+func EncodeOSMShortLink(latLng LatLng, zoom int) string {
+	// The north pole and the antimeridian are kept within 32 bits instead of wrapping around.
+	x := min32Bits((latLng.Longitude + 180) * (1 << 32) / 360)
+	y := min32Bits((latLng.Latitude + 90) * (1 << 32) / 180)
+	code := interleaveBits(x, y)
+	levels := zoom + osmShortLinkZoomPad
+	var sb strings.Builder
+	for i := 0; i < (levels+2)/3; i++ {
+		sb.WriteByte(osmShortLinkAlphabet[(code>>(58-6*i))&0x3f])
+	}
+	for i := 0; i < levels%3; i++ {
+		sb.WriteByte('-')
+	}
+	return sb.String()
+}
+
+// DecodeOSMShortLink decodes the code of an `osm.org/go/<code>` shortlink into the location and zoom it encodes.
+// The location is the south-west corner of the cell the code stands for.
+func DecodeOSMShortLink(code string) (LatLng, int, error) {
+	code = strings.ReplaceAll(code, "@", "~")
+	var x, y uint64
+	bits, dashes := 0, 0
+	for i, c := range code {
+		digit := strings.IndexRune(osmShortLinkAlphabet, c)
+		switch {
+		case digit >= 0 && dashes == 0:
+			for j := 0; j < 3; j++ {
+				x = x<<1 | uint64(digit>>(5-2*j))&1
+				y = y<<1 | uint64(digit>>(4-2*j))&1
+			}
+			bits += 3
+		case c == '-' && bits > 0 && dashes < 2:
+			dashes++
+		default:
+			return LatLng{}, 0, fmt.Errorf("invalid shortlink character %q at %d", c, i)
+		}
+	}
+	if bits == 0 || bits > 32 {
+		return LatLng{}, 0, fmt.Errorf("invalid shortlink length: %d", len(code))
+	}
+	x <<= 32 - bits
+	y <<= 32 - bits
+	latLng := LatLng{
+		Latitude:  float64(y)*180/(1<<32) - 90,
+		Longitude: float64(x)*360/(1<<32) - 180,
+	}
+	// One dash stands for two zoom levels less, two dashes for one.
+	zoom := bits - osmShortLinkZoomPad - (3-dashes)%3
+	return latLng, zoom, nil
+}
+
+// interleaveBits interleaves the lower 32 bits of x and y into 64 bits, each bit of x preceding the bit of y.
+func interleaveBits(x, y uint64) uint64 {
+	var code uint64
+	for i := 31; i >= 0; i-- {
+		code = code<<2 | (x>>i&1)<<1 | y>>i&1
+	}
+	return code
+}
+
+func min32Bits(v float64) uint64 {
+	if v >= 1<<32 {
+		return 1<<32 - 1
+	}
+	return uint64(v)
+}
@@ -0,0 +1,156 @@
+package maps
+
+import (
+	"math/rand"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOSMFromURL(t *testing.T) {
+	testCases := []struct {
+		name           string
+		inputURL       string
+		expectedLatLng LatLng
+		expectedZoom   int
+		expectedMarker bool
+		expectedError  string
+	}{
+		{
+			name:           "Map view",
+			inputURL:       "https://www.openstreetmap.org/#map=17/51.10694/17.07721",
+			expectedLatLng: LatLng{Latitude: 51.10694, Longitude: 17.07721},
+			expectedZoom:   17,
+		},
+		{
+			name:           "Map view with layers",
+			inputURL:       "https://www.openstreetmap.org/#map=12/-33.8688/151.2093&layers=C",
+			expectedLatLng: LatLng{Latitude: -33.8688, Longitude: 151.2093},
+			expectedZoom:   12,
+		},
+		{
+			name:           "Marker wins over map view",
+			inputURL:       "https://www.openstreetmap.org/?mlat=51.1069&mlon=17.0772#map=15/51.1/17.0",
+			expectedLatLng: LatLng{Latitude: 51.1069, Longitude: 17.0772},
+			expectedZoom:   15,
+			expectedMarker: true,
+		},
+		{
+			name:           "Node with marker",
+			inputURL:       "https://www.openstreetmap.org/node/2385226745?mlat=51.1069&mlon=17.0772",
+			expectedLatLng: LatLng{Latitude: 51.1069, Longitude: 17.0772},
+			expectedZoom:   osmDefaultZoom,
+			expectedMarker: true,
+		},
+		{
+			name:           "Node with map view",
+			inputURL:       "https://www.openstreetmap.org/node/2385226745#map=19/51.10694/17.07721",
+			expectedLatLng: LatLng{Latitude: 51.10694, Longitude: 17.07721},
+			expectedZoom:   19,
+		},
+		{
+			name:           "Legacy view",
+			inputURL:       "https://www.openstreetmap.org/?lat=51.1069&lon=17.0772&zoom=14",
+			expectedLatLng: LatLng{Latitude: 51.1069, Longitude: 17.0772},
+			expectedZoom:   14,
+		},
+		{
+			name:           "Shortlink",
+			inputURL:       "https://osm.org/go/0EEQjE--",
+			expectedLatLng: LatLng{Latitude: 51.510772705078125, Longitude: 0.054931640625},
+			expectedZoom:   9,
+		},
+		{
+			name:           "Shortlink with marker",
+			inputURL:       "https://osm.org/go/0EEQjE--?m=",
+			expectedLatLng: LatLng{Latitude: 51.510772705078125, Longitude: 0.054931640625},
+			expectedZoom:   9,
+			expectedMarker: true,
+		},
+		{
+			name:          "Invalid shortlink",
+			inputURL:      "https://osm.org/go/0EE!jE",
+			expectedError: "failed to decode shortlink",
+		},
+		{
+			name:          "Node without location",
+			inputURL:      "https://www.openstreetmap.org/node/2385226745",
+			expectedError: "failed to find lat lng for url",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			link, err := ParseOSMFromURL(u)
+
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			latLng, err := link.LatLng()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedLatLng, latLng)
+			assert.Equal(t, tc.expectedZoom, link.Zoom())
+			assert.Equal(t, tc.expectedMarker, link.Marker())
+		})
+	}
+}
+
+func TestOSMShortLink(t *testing.T) {
+	testCases := []struct {
+		name   string
+		latLng LatLng
+		zoom   int
+		code   string
+	}{
+		{name: "London", latLng: LatLng{Latitude: 51.51098, Longitude: 0.05499}, zoom: 9, code: "0EEQjE--"},
+		{name: "London zoomed out", latLng: LatLng{Latitude: 51.51098, Longitude: 0.05499}, zoom: 8, code: "0EEQjE-"},
+		{name: "London zoomed in", latLng: LatLng{Latitude: 51.51098, Longitude: 0.05499}, zoom: 10, code: "0EEQjE"},
+		{name: "Origin", latLng: LatLng{Latitude: 0, Longitude: 0}, zoom: 1, code: "wAA"},
+		{name: "North east corner", latLng: LatLng{Latitude: 90, Longitude: 180}, zoom: 5, code: "~~~~~-"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.code, EncodeOSMShortLink(tc.latLng, tc.zoom))
+
+			latLng, zoom, err := DecodeOSMShortLink(tc.code)
+			require.NoError(t, err)
+			assert.Equal(t, tc.zoom, zoom)
+			assert.InDelta(t, tc.latLng.Latitude, latLng.Latitude, 180*osmCellFraction(tc.zoom))
+			assert.InDelta(t, tc.latLng.Longitude, latLng.Longitude, 360*osmCellFraction(tc.zoom))
+		})
+	}
+}
+
+func TestOSMShortLink_RoundTrip(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		latLng := LatLng{Latitude: random.Float64()*180 - 90, Longitude: random.Float64()*360 - 180}
+		zoom := random.Intn(19) + 1
+
+		decoded, decodedZoom, err := DecodeOSMShortLink(EncodeOSMShortLink(latLng, zoom))
+
+		require.NoError(t, err)
+		require.Equal(t, zoom, decodedZoom)
+		require.InDelta(t, latLng.Latitude, decoded.Latitude, 180*osmCellFraction(zoom))
+		require.InDelta(t, latLng.Longitude, decoded.Longitude, 360*osmCellFraction(zoom))
+		// Old codes use `@` instead of `~`.
+		legacy, _, err := DecodeOSMShortLink(strings.ReplaceAll(EncodeOSMShortLink(latLng, zoom), "~", "@"))
+		require.NoError(t, err)
+		require.Equal(t, decoded, legacy)
+	}
+}
+
+// osmCellFraction is the part of the coordinate range covered by the cell of a shortlink, 3 bits per character.
+func osmCellFraction(zoom int) float64 {
+	return 1 / float64(uint64(1)<<(3*((zoom+osmShortLinkZoomPad+2)/3)))
+}
@@ -0,0 +1,137 @@
+package maps
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PlaceIdentifierKind tells which kind of identifier a Google Maps link refers to a place with.
+type PlaceIdentifierKind string
+
+const (
+	// PlaceCID is the decimal customer id of a place, e.g. `?cid=1234567890`.
+	PlaceCID PlaceIdentifierKind = "cid"
+	// PlaceFTID is the feature id of a place, e.g. `?ftid=0x47..:0x..`, whose second half is the hexadecimal cid.
+	PlaceFTID PlaceIdentifierKind = "ftid"
+	// PlacePlaceID is the Places API id of a place, e.g. `?q=place_id:ChIJ..`.
+	PlacePlaceID PlaceIdentifierKind = "place_id"
+)
+
+// PlaceIdentifier identifies a place without carrying its coordinates.
+type PlaceIdentifier struct {
+	Kind  PlaceIdentifierKind
+	Value string
+}
+
+const (
+	placeIDPrefix  = "place_id:"
+	ftidRegex      = `^0x[0-9a-fA-F]+:(0x[0-9a-fA-F]+)$`
+	placeNameRegex = `<meta content="([^"]+)" itemprop="name">|<meta property="og:title" content="([^"]+)"|<title>([^<]+)</title>`
+	// googleMapsTitleSuffix is appended by Google to the title of place pages.
+	googleMapsTitleSuffix = " - Google Maps"
+	// placeNameSeparator separates the name of a place from its address in page titles.
+	placeNameSeparator = " · "
+)
+
+var (
+	ftidPattern      = regexp.MustCompile(ftidRegex)
+	placeNamePattern = regexp.MustCompile(placeNameRegex)
+)
+
+// ParsePlaceIdentifier recognises the `cid=`, `ftid=` and `place_id:` forms of Google Maps links.
+func ParsePlaceIdentifier(u *url.URL) (PlaceIdentifier, bool) {
+	q := u.Query()
+	if cid := q.Get("cid"); cid != "" {
+		if _, err := strconv.ParseUint(cid, 10, 64); err == nil {
+			return PlaceIdentifier{Kind: PlaceCID, Value: cid}, true
+		}
+	}
+	if ftid := q.Get("ftid"); ftidPattern.MatchString(ftid) {
+		return PlaceIdentifier{Kind: PlaceFTID, Value: ftid}, true
+	}
+	for _, name := range []string{"q", "query"} {
+		if v := q.Get(name); strings.HasPrefix(v, placeIDPrefix) {
+			return PlaceIdentifier{Kind: PlacePlaceID, Value: strings.TrimPrefix(v, placeIDPrefix)}, true
+		}
+	}
+	for _, name := range []string{"query_place_id", "destination_place_id"} {
+		if v := q.Get(name); v != "" {
+			return PlaceIdentifier{Kind: PlacePlaceID, Value: v}, true
+		}
+	}
+	return PlaceIdentifier{}, false
+}
+
+// CID returns the decimal customer id of cid and ftid identifiers.
+func (p PlaceIdentifier) CID() (string, bool) {
+	switch p.Kind {
+	case PlaceCID:
+		return p.Value, true
+	case PlaceFTID:
+		matches := ftidPattern.FindStringSubmatch(p.Value)
+		if matches == nil {
+			return "", false
+		}
+		cid, err := strconv.ParseUint(strings.TrimPrefix(matches[1], "0x"), 16, 64)
+		if err != nil {
+			return "", false
+		}
+		return strconv.FormatUint(cid, 10), true
+	default:
+		return "", false
+	}
+}
+
+// placeURL builds the place page of the identifier on the host of the given link.
+func (p PlaceIdentifier) placeURL(u *url.URL) *url.URL {
+	placeURL := &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}
+	if cid, ok := p.CID(); ok {
+		placeURL.RawQuery = url.Values{"cid": {cid}}.Encode()
+		return placeURL
+	}
+	placeURL.Path = "/maps/place/"
+	placeURL.RawQuery = url.Values{"q": {placeIDPrefix + p.Value}}.Encode()
+	return placeURL
+}
+
+// ResolvePlace follows the place identifier of the link to its place page and extracts the coordinates
+// along with the name of the place.
+func ResolvePlace(ctx context.Context, u *url.URL, id PlaceIdentifier, toContent UrlToContent) (*GoogleMapsLink, error) {
+	placeURL := id.placeURL(u)
+	content, err := toContent(ctx, placeURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get content of place: %s, error: %w", placeURL.String(), err)
+	}
+	link, ok := googleMapsFromContent(content)
+	if !ok {
+		return nil, fmt.Errorf("failed to find lat lng for place %s: %s", id.Kind, id.Value)
+	}
+	return link, nil
+}
+
+// placeName extracts the name of the place from the meta tags or the title of a place page.
+func placeName(content string) string {
+	matches := placeNamePattern.FindStringSubmatch(content)
+	if matches == nil {
+		return ""
+	}
+	var name string
+	for _, m := range matches[1:] {
+		if m != "" {
+			name = m
+			break
+		}
+	}
+	name = html.UnescapeString(name)
+	name = strings.TrimSuffix(name, googleMapsTitleSuffix)
+	name = strings.SplitN(name, placeNameSeparator, 2)[0]
+	if name == "Google Maps" {
+		return ""
+	}
+	return strings.TrimSpace(name)
+}
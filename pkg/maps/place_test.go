@@ -0,0 +1,126 @@
+package maps
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const placePage = `<html><head><title>Hala Stulecia - Google Maps</title>` +
+	`<meta content="Hala Stulecia · Wystawowa 1, 51-618 Wrocław" itemprop="name">` +
+	`<meta content="https://maps.google.com/maps/api/staticmap?center=51.1069402%2C17.0772095&amp;zoom=15" itemprop="image">` +
+	`</head></html>`
+
+func TestParsePlaceIdentifier(t *testing.T) {
+	testCases := []struct {
+		name        string
+		inputURL    string
+		expectedID  PlaceIdentifier
+		expectedCID string
+	}{
+		{
+			name:        "CID",
+			inputURL:    "https://maps.google.com/?cid=1234567890",
+			expectedID:  PlaceIdentifier{Kind: PlaceCID, Value: "1234567890"},
+			expectedCID: "1234567890",
+		},
+		{
+			name:        "FTID",
+			inputURL:    "https://maps.google.com/?ftid=0x470fe9c2d4b58abf:0x499602d2",
+			expectedID:  PlaceIdentifier{Kind: PlaceFTID, Value: "0x470fe9c2d4b58abf:0x499602d2"},
+			expectedCID: "1234567890",
+		},
+		{
+			name:       "Place ID in query",
+			inputURL:   "https://www.google.com/maps/place/?q=place_id:ChIJN1t_tDeuEmsRUsoyG83frY4",
+			expectedID: PlaceIdentifier{Kind: PlacePlaceID, Value: "ChIJN1t_tDeuEmsRUsoyG83frY4"},
+		},
+		{
+			name:       "Place ID in search API",
+			inputURL:   "https://www.google.com/maps/search/?api=1&query=Google&query_place_id=ChIJN1t_tDeuEmsRUsoyG83frY4",
+			expectedID: PlaceIdentifier{Kind: PlacePlaceID, Value: "ChIJN1t_tDeuEmsRUsoyG83frY4"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			id, ok := ParsePlaceIdentifier(u)
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedID, id)
+
+			cid, ok := id.CID()
+			assert.Equal(t, tc.expectedCID != "", ok)
+			assert.Equal(t, tc.expectedCID, cid)
+		})
+	}
+}
+
+func TestParseGoogleMapsFromURL_PlaceIdentifier(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" && r.URL.Query().Get("cid") == "1234567890" ||
+			r.URL.Path == "/maps/place/" && r.URL.Query().Get("q") == "place_id:ChIJN1t_tDeuEmsRUsoyG83frY4" {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(placePage))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer testServer.Close()
+
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		{name: "CID", query: "?cid=1234567890"},
+		{name: "FTID", query: "?ftid=0x470fe9c2d4b58abf:0x499602d2"},
+		{name: "Place ID", query: "?q=place_id:ChIJN1t_tDeuEmsRUsoyG83frY4"},
+	}
+
+	httpClient := &http.Client{}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(testServer.URL + tc.query)
+			require.NoError(t, err)
+
+			link, err := ParseGoogleMapsFromURL(context.Background(), u, HttpGetToInput(httpClient))
+			require.NoError(t, err)
+			latLng := LatLng{Latitude: 51.1069402, Longitude: 17.0772095}
+			assert.Equal(t, &GoogleMapsLink{
+				latLng:     latLng,
+				name:       "Hala Stulecia",
+				candidates: []Candidate{{LatLng: latLng, Source: SourceBody, Confidence: confidenceBodyView}},
+			}, link)
+		})
+	}
+}
+
+func TestParseGoogleMapsFromURL_UnknownPlace(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("<html><title>Google Maps</title></html>"))
+	}))
+	defer testServer.Close()
+
+	u, err := url.Parse(testServer.URL + "?cid=1")
+	require.NoError(t, err)
+
+	_, err = ParseGoogleMapsFromURL(context.Background(), u, HttpGetToInput(&http.Client{}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to find lat lng for place cid: 1")
+}
+
+func TestPlaceName(t *testing.T) {
+	assert.Equal(t, "Hala Stulecia", placeName(placePage))
+	assert.Equal(t, "Café & Rosé", placeName(`<title>Café &amp; Rosé - Google Maps</title>`))
+	assert.Equal(t, "Café", placeName(`<meta property="og:title" content="Café · Rynek 1">`))
+	assert.Equal(t, "", placeName("<title>Google Maps</title>"))
+}
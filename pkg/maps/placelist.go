@@ -0,0 +1,241 @@
+package maps
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNoPlaces is returned for My Maps maps and saved lists without a single place.
+var ErrNoPlaces = errors.New("no places in list")
+
+const (
+	// myMapsPathPrefix prefixes the `/maps/d/viewer?mid=` and `/maps/d/edit?mid=` links of My Maps maps.
+	myMapsPathPrefix = "/maps/d/"
+	// myMapsKMLPath is the KML export of a My Maps map, `forcekml=1` asking for plain KML rather than KMZ.
+	myMapsKMLPath = "/maps/d/kml"
+	// placeListPathPrefix prefixes the links of saved lists, e.g. `/maps/placelists/list/<id>`.
+	placeListPathPrefix = "/maps/placelists/list/"
+	// placeListPayloadPath returns the places of the saved list given in the `pb=` parameter.
+	placeListPayloadPath = "/maps/preview/entitylist/getlist"
+	// placeListPayloadPb asks for up to 500 places of the saved list of the given identifier.
+	placeListPayloadPb = "!1m4!1s%s!2e1!3m1!1e1!2e2!3e2!4i500!16b1"
+	// jsonSafetyPrefix is prepended to the JSON payloads of Google so that they cannot be run as scripts.
+	jsonSafetyPrefix = ")]}'"
+)
+
+// Place is a named location of a My Maps map or a saved list.
+type Place struct {
+	latLng LatLng
+	name   string
+}
+
+func (p Place) LatLng() (LatLng, error) {
+	return p.latLng, nil
+}
+
+// Name returns the name of the place.
+func (p Place) Name() string {
+	return p.name
+}
+
+// PlaceList is a My Maps map or a saved list, holding all of its places in order.
+type PlaceList struct {
+	name   string
+	places []Place
+}
+
+// LatLng returns the location of the first place of the list.
+func (l *PlaceList) LatLng() (LatLng, error) {
+	if len(l.places) == 0 {
+		return LatLng{}, ErrNoPlaces
+	}
+	return l.places[0].latLng, nil
+}
+
+// Name returns the title of the map or the list.
+func (l *PlaceList) Name() string {
+	return l.name
+}
+
+// Places returns all places of the list.
+func (l *PlaceList) Places() []Place {
+	return l.places
+}
+
+// PlaceListError is returned when resolving a link leads to a My Maps map or a saved list rather than to a single place.
+// The places are parsed from its URL with ParsePlaceListFromURL.
+type PlaceListError struct {
+	URL *url.URL
+}
+
+func (e *PlaceListError) Error() string {
+	return fmt.Sprintf("url leads to a list of places: %s", e.URL.String())
+}
+
+// IsPlaceListURL tells whether the URL links to a My Maps map or a saved list.
+func IsPlaceListURL(u *url.URL) bool {
+	if _, ok := myMapsID(u); ok {
+		return true
+	}
+	_, ok := placeListID(u)
+	return ok
+}
+
+// myMapsID returns the `mid=` identifier of a My Maps map.
+func myMapsID(u *url.URL) (string, bool) {
+	if !strings.HasPrefix(u.Path, myMapsPathPrefix) {
+		return "", false
+	}
+	mid := u.Query().Get("mid")
+	return mid, mid != ""
+}
+
+// placeListID returns the identifier of a saved list, given in the path or in the `!11m2!2s<id>` entry of the data.
+func placeListID(u *url.URL) (string, bool) {
+	if id, ok := strings.CutPrefix(u.Path, placeListPathPrefix); ok {
+		id = strings.SplitN(id, "/", 2)[0]
+		return id, id != ""
+	}
+	data, ok := dataFromURL(u)
+	if !ok {
+		return "", false
+	}
+	return data.ListID()
+}
+
+// ParsePlaceListFromURL fetches all places of a My Maps map from its KML export, or those of a saved list from its
+// payload, with toContent.
+func ParsePlaceListFromURL(ctx context.Context, u *url.URL, toContent UrlToContent) (*PlaceList, error) {
+	var list *PlaceList
+	var err error
+	if mid, ok := myMapsID(u); ok {
+		list, err = fetchMyMaps(ctx, mid, toContent)
+	} else if id, ok := placeListID(u); ok {
+		list, err = fetchPlaceList(ctx, id, toContent)
+	} else {
+		return nil, fmt.Errorf("failed to find list in url: %s", u.String())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get places of url: %s, error: %w", u.String(), err)
+	}
+	if len(list.places) == 0 {
+		return nil, fmt.Errorf("failed to find places of url: %s, error: %w", u.String(), ErrNoPlaces)
+	}
+	return list, nil
+}
+
+// kmlPlacemark is a placemark of a KML document, only points being taken as places.
+type kmlPlacemark struct {
+	Name        string `xml:"name"`
+	Coordinates string `xml:"Point>coordinates"`
+}
+
+func fetchMyMaps(ctx context.Context, mid string, toContent UrlToContent) (*PlaceList, error) {
+	q := url.Values{}
+	q.Set("mid", mid)
+	q.Set("forcekml", "1")
+	content, err := toContent(ctx, &url.URL{Scheme: "https", Host: googleMapsHost, Path: myMapsKMLPath, RawQuery: q.Encode()})
+	if err != nil {
+		return nil, err
+	}
+	return parseKML(content)
+}
+
+// parseKML takes the placemarks with a point in document order, wherever the folders of the layers nest them,
+// skipping lines and polygons. The name of the document is the title of the map.
+func parseKML(content string) (*PlaceList, error) {
+	list := &PlaceList{}
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	// depth is 2 within the document, the kml element wrapping it.
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return list, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode kml: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Local == "Placemark":
+				var placemark kmlPlacemark
+				if err := decoder.DecodeElement(&placemark, &t); err != nil {
+					return nil, fmt.Errorf("failed to decode kml placemark: %w", err)
+				}
+				// Coordinates are given as `lng,lat,altitude`.
+				if latLng, _, ok := lngLatLabel(strings.TrimSpace(placemark.Coordinates)); ok {
+					list.places = append(list.places, Place{latLng: latLng, name: strings.TrimSpace(placemark.Name)})
+				}
+				continue
+			case t.Name.Local == "name" && depth == 2:
+				var name string
+				if err := decoder.DecodeElement(&name, &t); err != nil {
+					return nil, fmt.Errorf("failed to decode kml name: %w", err)
+				}
+				list.name = strings.TrimSpace(name)
+				continue
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
+
+func fetchPlaceList(ctx context.Context, id string, toContent UrlToContent) (*PlaceList, error) {
+	q := url.Values{}
+	q.Set("authuser", "0")
+	q.Set("hl", "en")
+	q.Set("pb", fmt.Sprintf(placeListPayloadPb, id))
+	content, err := toContent(ctx, &url.URL{Scheme: "https", Host: googleMapsHost, Path: placeListPayloadPath, RawQuery: q.Encode()})
+	if err != nil {
+		return nil, err
+	}
+	return parsePlaceListPayload(content)
+}
+
+// parsePlaceListPayload reads the saved list payload, laid out as
+// `[[id, ..., title, ..., [[null, [null, null, "", null, address, [null, null, lat, lng]], name], ...]]]`
+// with the title at index 4 and the places at index 8.
+func parsePlaceListPayload(content string) (*PlaceList, error) {
+	content = strings.TrimPrefix(strings.TrimSpace(content), jsonSafetyPrefix)
+	var payload []any
+	if err := json.Unmarshal([]byte(content), &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode list payload: %w", err)
+	}
+	list := &PlaceList{}
+	list.name, _ = jsonIndex(payload, 0, 4).(string)
+	entries, _ := jsonIndex(payload, 0, 8).([]any)
+	for _, entry := range entries {
+		lat, latOk := jsonIndex(entry, 1, 5, 2).(float64)
+		lng, lngOk := jsonIndex(entry, 1, 5, 3).(float64)
+		latLng := LatLng{Latitude: lat, Longitude: lng}
+		if !latOk || !lngOk || !latLng.Valid() {
+			continue
+		}
+		name, _ := jsonIndex(entry, 2).(string)
+		list.places = append(list.places, Place{latLng: latLng, name: strings.TrimSpace(name)})
+	}
+	return list, nil
+}
+
+// jsonIndex walks down the nested arrays of a decoded JSON value, returning nil when any index is out of reach.
+func jsonIndex(v any, indexes ...int) any {
+	for _, i := range indexes {
+		array, ok := v.([]any)
+		if !ok || i >= len(array) {
+			return nil
+		}
+		v = array[i]
+	}
+	return v
+}
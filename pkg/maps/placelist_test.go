@@ -0,0 +1,115 @@
+package maps
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePlaceListFromURL(t *testing.T) {
+	httpClient := newStubClient(t)
+	myMapsPlaces := []Place{
+		{latLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095}, name: "Hala Stulecia"},
+		{latLng: LatLng{Latitude: 51.11, Longitude: 17.032}, name: "Rynek"},
+		{latLng: LatLng{Latitude: 51.0983, Longitude: 17.0365}, name: "Dworzec Główny"},
+	}
+	savedListPlaces := []Place{
+		{latLng: LatLng{Latitude: 51.11, Longitude: 17.032}, name: "Bernard"},
+		{latLng: LatLng{Latitude: 51.1085, Longitude: 17.0305}, name: "Konspira"},
+	}
+
+	testCases := []struct {
+		name           string
+		inputURL       string
+		expectedName   string
+		expectedPlaces []Place
+		expectedError  string
+	}{
+		{
+			name:           "My Maps viewer",
+			inputURL:       "https://www.google.com/maps/d/viewer?mid=1a2B3c4D5e6F7g8H9i0J&ll=51.1,17.05&z=13",
+			expectedName:   "Wroclaw meetup",
+			expectedPlaces: myMapsPlaces,
+		},
+		{
+			name:           "My Maps editor of a signed in user",
+			inputURL:       "https://www.google.com/maps/d/u/0/edit?mid=1a2B3c4D5e6F7g8H9i0J&usp=sharing",
+			expectedName:   "Wroclaw meetup",
+			expectedPlaces: myMapsPlaces,
+		},
+		{
+			name:           "Saved list",
+			inputURL:       "https://www.google.com/maps/placelists/list/AbCdEf123?g_ep=CAISDTYuMTE5&g_st=ic",
+			expectedName:   "Lunch spots",
+			expectedPlaces: savedListPlaces,
+		},
+		{
+			name:           "Saved list in data",
+			inputURL:       "https://www.google.com/maps/@51.1,17.03,14z/data=!4m3!11m2!2sAbCdEf123!3e3",
+			expectedName:   "Lunch spots",
+			expectedPlaces: savedListPlaces,
+		},
+		{
+			name:          "Not a list",
+			inputURL:      "https://www.google.com/maps/place/Hala+Stulecia",
+			expectedError: "failed to find list in url",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			list, err := ParsePlaceListFromURL(context.Background(), u, HttpGetToInput(httpClient))
+
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedName, list.Name())
+			assert.Equal(t, tc.expectedPlaces, list.Places())
+			latLng, err := list.LatLng()
+			require.NoError(t, err)
+			assert.Equal(t, list.Places()[0].latLng, latLng)
+		})
+	}
+}
+
+func TestParsePlaceListFromURL_NoPlaces(t *testing.T) {
+	u, err := url.Parse("https://www.google.com/maps/d/viewer?mid=1a2B3c4D5e6F7g8H9i0J")
+	require.NoError(t, err)
+	toContent := func(context.Context, *url.URL) (string, error) {
+		return `<kml><Document><name>Empty</name><Placemark><name>Area</name><Polygon/></Placemark></Document></kml>`, nil
+	}
+
+	_, err = ParsePlaceListFromURL(context.Background(), u, toContent)
+
+	assert.ErrorIs(t, err, ErrNoPlaces)
+}
+
+func TestRedirectResolver_PlaceList(t *testing.T) {
+	httpClient := newRewriteClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://www.google.com/maps/placelists/list/AbCdEf123?g_ep=CAISDTYuMTE5&g_st=ic", http.StatusFound)
+	}))
+
+	for _, inputURL := range []string{
+		"https://maps.app.goo.gl/list",
+		"https://www.google.com/maps/d/viewer?mid=1a2B3c4D5e6F7g8H9i0J&ll=51.1,17.05&z=13",
+	} {
+		u, err := url.Parse(inputURL)
+		require.NoError(t, err)
+
+		_, err = NewRedirectResolver(httpClient).Resolve(context.Background(), u)
+
+		var listErr *PlaceListError
+		require.ErrorAs(t, err, &listErr, inputURL)
+		assert.True(t, IsPlaceListURL(listErr.URL), inputURL)
+	}
+}
@@ -0,0 +1,317 @@
+package maps
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidPlusCode is returned for codes not following the Open Location Code format.
+var ErrInvalidPlusCode = errors.New("invalid plus code")
+
+const (
+	plusCodeAlphabet  = "23456789CFGHJMPQRVWX"
+	plusCodeSeparator = '+'
+	plusCodePadding   = '0'
+	// plusCodeSeparatorPosition is the number of digits before the separator in full codes.
+	plusCodeSeparatorPosition = 8
+	// plusCodePairLength is the number of digits encoding latitude and longitude in pairs, in a 20x20 grid each.
+	plusCodePairLength = 10
+	// plusCodeMaxLength is the number of digits past which the precision does not grow, each grid digit refining a 5x4 grid.
+	plusCodeMaxLength  = 15
+	plusCodeGridLength = plusCodeMaxLength - plusCodePairLength
+	plusCodeBase       = 20
+	plusCodeGridRows   = 5
+	plusCodeGridCols   = 4
+	// plusCodePairPrecision is the number of pair cells per degree at full pair length.
+	plusCodePairPrecision = 8000
+	// plusCodePairFirstPlaceValue is the place value of the first pair, 20^4.
+	plusCodePairFirstPlaceValue = 160000
+	// plusCodeLatPrecision and plusCodeLngPrecision are the number of cells per degree at the maximal length.
+	plusCodeLatPrecision = plusCodePairPrecision * 3125 // 5^5
+	plusCodeLngPrecision = plusCodePairPrecision * 1024 // 4^5
+	// DefaultPlusCodeLength gives cells of about 14x14 metres, as shown by Google Maps.
+	DefaultPlusCodeLength = 10
+	// plusCodeMinShortPrefix and plusCodeMinShortSuffix are the digits required around the separator of short codes in text.
+	plusCodeMinShortPrefix = 4
+	plusCodeMinShortSuffix = 2
+	// plusCodeLocalityEnd ends the locality following a short code in text, at the end of the line or the sentence.
+	// Periods end it too, unless they end an abbreviation, see localityEnd.
+	plusCodeLocalityEnd = "!?;\n"
+	// plusCodeAbbreviationLength is the longest word a period abbreviates, e.g. `St.` or the `C.` of `D.C.`.
+	plusCodeAbbreviationLength = 2
+	plusCodeTextRegex          = `(?i)(?:^|[^0-9A-Z+])([23456789CFGHJMPQRVWX]{2,8}0{0,6}\+[23456789CFGHJMPQRVWX]{0,7})(?:$|[^0-9A-Z+])`
+)
+
+var plusCodeTextPattern = regexp.MustCompile(plusCodeTextRegex)
+
+// PlusCode is an Open Location Code, either full, e.g. `8FVC9G8F+6X`, or short, e.g. `9G8F+6X`.
+// Short codes have to be recovered with RecoverPlusCode before they locate anything.
+type PlusCode string
+
+// LatLng returns the center of the area of a full code.
+func (c PlusCode) LatLng() (LatLng, error) {
+	if !c.IsFull() {
+		return LatLng{}, fmt.Errorf("failed to locate %s: %w", c, ErrInvalidPlusCode)
+	}
+	area, err := DecodePlusCode(string(c))
+	if err != nil {
+		return LatLng{}, err
+	}
+	return area.Center(), nil
+}
+
+// IsValid tells whether the code is a valid full or short code.
+func (c PlusCode) IsValid() bool {
+	code := strings.ToUpper(string(c))
+	separator := strings.IndexRune(code, plusCodeSeparator)
+	if separator < 0 || separator != strings.LastIndexByte(code, plusCodeSeparator) ||
+		separator > plusCodeSeparatorPosition || separator%2 == 1 {
+		return false
+	}
+	if padding := strings.IndexByte(code, plusCodePadding); padding >= 0 {
+		// Padding fills whole pairs up to the separator, which ends the code.
+		if padding == 0 || separator < plusCodeSeparatorPosition || separator != len(code)-1 {
+			return false
+		}
+		padded := code[padding:separator]
+		if len(padded)%2 == 1 || strings.Trim(padded, string(plusCodePadding)) != "" {
+			return false
+		}
+	}
+	// A single digit after the separator is not allowed.
+	if len(code)-separator-1 == 1 {
+		return false
+	}
+	for _, r := range strings.NewReplacer(string(plusCodeSeparator), "", string(plusCodePadding), "").Replace(code) {
+		if !strings.ContainsRune(plusCodeAlphabet, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsFull tells whether the code is a valid full code.
+func (c PlusCode) IsFull() bool {
+	if !c.IsValid() || strings.IndexRune(string(c), plusCodeSeparator) != plusCodeSeparatorPosition {
+		return false
+	}
+	code := strings.ToUpper(string(c))
+	// The first pair must not point beyond the poles or the antimeridian.
+	return strings.IndexByte(plusCodeAlphabet, code[0])*plusCodeBase < 180 &&
+		(len(code) < 2 || code[1] == plusCodePadding || strings.IndexByte(plusCodeAlphabet, code[1])*plusCodeBase < 360)
+}
+
+// IsShort tells whether the code is a valid short code, missing the leading digits.
+func (c PlusCode) IsShort() bool {
+	return c.IsValid() && strings.IndexRune(string(c), plusCodeSeparator) < plusCodeSeparatorPosition
+}
+
+// CodeArea is the area a plus code stands for.
+type CodeArea struct {
+	LatLo, LngLo, LatHi, LngHi float64
+	CodeLength                 int
+}
+
+// Center returns the center of the area, kept within the valid ranges.
+func (a CodeArea) Center() LatLng {
+	return LatLng{
+		Latitude:  math.Min((a.LatLo+a.LatHi)/2, 90),
+		Longitude: math.Min((a.LngLo+a.LngHi)/2, 180),
+	}
+}
+
+// EncodePlusCode encodes the location into a full code of the given number of digits,
+// which is 2, 4, 6, 8 or anything from 10 to 15.
+func EncodePlusCode(latLng LatLng, codeLength int) (PlusCode, error) {
+	if codeLength < 2 || (codeLength < plusCodePairLength && codeLength%2 == 1) {
+		return "", fmt.Errorf("invalid plus code length %d: %w", codeLength, ErrInvalidPlusCode)
+	}
+	if codeLength > plusCodeMaxLength {
+		codeLength = plusCodeMaxLength
+	}
+	// Scale to integers first, avoiding floating point errors at the cell boundaries.
+	latVal := int64(math.Round((clipLatitude(latLng.Latitude)+90)*plusCodeLatPrecision*1e6) / 1e6)
+	lngVal := int64(math.Round((normalizeLongitude(latLng.Longitude)+180)*plusCodeLngPrecision*1e6) / 1e6)
+	// The north pole belongs to the cell below it.
+	if latVal >= 180*plusCodeLatPrecision {
+		latVal = 180*plusCodeLatPrecision - 1
+	}
+	digits := make([]byte, plusCodeMaxLength)
+	for i := plusCodeMaxLength - 1; i >= plusCodePairLength; i-- {
+		digits[i] = plusCodeAlphabet[(latVal%plusCodeGridRows)*plusCodeGridCols+lngVal%plusCodeGridCols]
+		latVal /= plusCodeGridRows
+		lngVal /= plusCodeGridCols
+	}
+	for i := plusCodePairLength - 2; i >= 0; i -= 2 {
+		digits[i+1] = plusCodeAlphabet[lngVal%plusCodeBase]
+		digits[i] = plusCodeAlphabet[latVal%plusCodeBase]
+		latVal /= plusCodeBase
+		lngVal /= plusCodeBase
+	}
+	if codeLength < plusCodeSeparatorPosition {
+		return PlusCode(string(digits[:codeLength]) + strings.Repeat(string(plusCodePadding), plusCodeSeparatorPosition-codeLength) + string(plusCodeSeparator)), nil
+	}
+	return PlusCode(string(digits[:plusCodeSeparatorPosition]) + string(plusCodeSeparator) + string(digits[plusCodeSeparatorPosition:codeLength])), nil
+}
+
+// DecodePlusCode decodes a full code into the area it stands for.
+func DecodePlusCode(code string) (CodeArea, error) {
+	if !PlusCode(code).IsFull() {
+		return CodeArea{}, fmt.Errorf("failed to decode %s: %w", code, ErrInvalidPlusCode)
+	}
+	clean := strings.NewReplacer(string(plusCodeSeparator), "", string(plusCodePadding), "").Replace(strings.ToUpper(code))
+	if len(clean) > plusCodeMaxLength {
+		clean = clean[:plusCodeMaxLength]
+	}
+	latVal := int64(-90 * plusCodePairPrecision)
+	lngVal := int64(-180 * plusCodePairPrecision)
+	placeValue := int64(plusCodePairFirstPlaceValue)
+	pairs := len(clean)
+	if pairs > plusCodePairLength {
+		pairs = plusCodePairLength
+	}
+	for i := 0; i < pairs; i += 2 {
+		latVal += int64(strings.IndexByte(plusCodeAlphabet, clean[i])) * placeValue
+		lngVal += int64(strings.IndexByte(plusCodeAlphabet, clean[i+1])) * placeValue
+		if i < pairs-2 {
+			placeValue /= plusCodeBase
+		}
+	}
+	latPrecision := float64(placeValue) / plusCodePairPrecision
+	lngPrecision := float64(placeValue) / plusCodePairPrecision
+
+	var extraLat, extraLng int64
+	if len(clean) > plusCodePairLength {
+		rowValue := int64(625) // 5^4
+		colValue := int64(256) // 4^4
+		for i := plusCodePairLength; i < len(clean); i++ {
+			digit := int64(strings.IndexByte(plusCodeAlphabet, clean[i]))
+			extraLat += digit / plusCodeGridCols * rowValue
+			extraLng += digit % plusCodeGridCols * colValue
+			if i < len(clean)-1 {
+				rowValue /= plusCodeGridRows
+				colValue /= plusCodeGridCols
+			}
+		}
+		latPrecision = float64(rowValue) / plusCodeLatPrecision
+		lngPrecision = float64(colValue) / plusCodeLngPrecision
+	}
+	lat := float64(latVal)/plusCodePairPrecision + float64(extraLat)/plusCodeLatPrecision
+	lng := float64(lngVal)/plusCodePairPrecision + float64(extraLng)/plusCodeLngPrecision
+	return CodeArea{
+		LatLo:      lat,
+		LngLo:      lng,
+		LatHi:      lat + latPrecision,
+		LngHi:      lng + lngPrecision,
+		CodeLength: len(clean),
+	}, nil
+}
+
+// RecoverPlusCode recovers the full code of a short code, taking the leading digits from the reference location.
+// Of the candidate areas, the one closest to the reference wins, so references near the edge of a cell still work.
+func RecoverPlusCode(code PlusCode, reference LatLng) (PlusCode, error) {
+	if !code.IsShort() {
+		if code.IsFull() {
+			return PlusCode(strings.ToUpper(string(code))), nil
+		}
+		return "", fmt.Errorf("failed to recover %s: %w", code, ErrInvalidPlusCode)
+	}
+	refLat := clipLatitude(reference.Latitude)
+	refLng := normalizeLongitude(reference.Longitude)
+	paddingLength := plusCodeSeparatorPosition - strings.IndexRune(string(code), plusCodeSeparator)
+	// The resolution of the missing digits, in degrees.
+	resolution := math.Pow(plusCodeBase, 2-float64(paddingLength)/2)
+	half := resolution / 2
+
+	prefix, err := EncodePlusCode(LatLng{Latitude: refLat, Longitude: refLng}, plusCodePairLength)
+	if err != nil {
+		return "", err
+	}
+	area, err := DecodePlusCode(string(prefix)[:paddingLength] + strings.ToUpper(string(code)))
+	if err != nil {
+		return "", err
+	}
+	center := area.Center()
+	switch {
+	case refLat+half < center.Latitude && center.Latitude-resolution >= -90:
+		center.Latitude -= resolution
+	case refLat-half > center.Latitude && center.Latitude+resolution <= 90:
+		center.Latitude += resolution
+	}
+	switch {
+	case refLng+half < center.Longitude:
+		center.Longitude -= resolution
+	case refLng-half > center.Longitude:
+		center.Longitude += resolution
+	}
+	return EncodePlusCode(center, area.CodeLength)
+}
+
+// FindPlusCode finds the first valid plus code in the text, along with the locality following it,
+// which short codes are relative to, e.g. `Wrocław` in `9G8F+6X Wrocław, see you at 5pm`, and the offset it starts at.
+func FindPlusCode(text string) (PlusCode, string, int, bool) {
+	for _, match := range plusCodeTextPattern.FindAllStringSubmatchIndex(text, -1) {
+		code := PlusCode(strings.ToUpper(text[match[2]:match[3]]))
+		separator := strings.IndexRune(string(code), plusCodeSeparator)
+		// Short codes shorter than shared by Google Maps are more likely anything but plus codes.
+		short := code.IsShort() && separator >= plusCodeMinShortPrefix && len(code)-separator-1 >= plusCodeMinShortSuffix
+		if code.IsFull() || short {
+			return code, plusCodeLocality(text[match[3]:]), match[2], true
+		}
+	}
+	return "", "", -1, false
+}
+
+// plusCodeLocality takes the locality from the start of the text up to the end of the line or the sentence.
+// The locality is made of capitalized parts, e.g. `Wrocław, Poland`, so it ends at the first part in lower case.
+func plusCodeLocality(text string) string {
+	text = text[:localityEnd(text)]
+	var parts []string
+	for _, part := range strings.Split(strings.TrimLeft(text, " ,\t"), ",") {
+		part = strings.TrimSpace(part)
+		first, _ := utf8.DecodeRuneInString(part)
+		if part == "" || unicode.IsLower(first) {
+			break
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// localityEnd finds the end of the line or the sentence, a period ending a sentence when it follows a word longer than
+// an abbreviation and comes last or before a space, so that neither `St. Gallen` nor `Washington, D.C.` is cut short.
+func localityEnd(text string) int {
+	letters := 0
+	for i, r := range text {
+		switch {
+		case strings.ContainsRune(plusCodeLocalityEnd, r):
+			return i
+		case r == '.':
+			next, _ := utf8.DecodeRuneInString(text[i+1:])
+			if letters > plusCodeAbbreviationLength && (i+1 == len(text) || unicode.IsSpace(next)) {
+				return i
+			}
+		}
+		if unicode.IsLetter(r) {
+			letters++
+		} else {
+			letters = 0
+		}
+	}
+	return len(text)
+}
+
+func clipLatitude(lat float64) float64 {
+	return math.Min(90, math.Max(-90, lat))
+}
+
+// normalizeLongitude brings the longitude within [-180, 180).
+func normalizeLongitude(lng float64) float64 {
+	return math.Mod(math.Mod(lng+180, 360)+360, 360) - 180
+}
@@ -0,0 +1,162 @@
+package maps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodePlusCode(t *testing.T) {
+	testCases := []struct {
+		latLng       LatLng
+		codeLength   int
+		expectedCode PlusCode
+	}{
+		{latLng: LatLng{Latitude: 20.375, Longitude: 2.775}, codeLength: 6, expectedCode: "7FG49Q00+"},
+		{latLng: LatLng{Latitude: 20.3700625, Longitude: 2.7821875}, codeLength: 10, expectedCode: "7FG49QCJ+2V"},
+		{latLng: LatLng{Latitude: 20.3701125, Longitude: 2.782234375}, codeLength: 11, expectedCode: "7FG49QCJ+2VX"},
+		{latLng: LatLng{Latitude: 47.0000625, Longitude: 8.0000625}, codeLength: 10, expectedCode: "8FVC2222+22"},
+		{latLng: LatLng{Latitude: -41.2730625, Longitude: 174.7859375}, codeLength: 10, expectedCode: "4VCPPQGP+Q9"},
+		{latLng: LatLng{Latitude: 90, Longitude: 1}, codeLength: 4, expectedCode: "CFX30000+"},
+		{latLng: LatLng{Latitude: 1, Longitude: 180}, codeLength: 4, expectedCode: "62H20000+"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.expectedCode), func(t *testing.T) {
+			code, err := EncodePlusCode(tc.latLng, tc.codeLength)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCode, code)
+		})
+	}
+}
+
+func TestDecodePlusCode(t *testing.T) {
+	testCases := []struct {
+		code         string
+		expectedArea CodeArea
+	}{
+		{code: "7FG49Q00+", expectedArea: CodeArea{LatLo: 20.35, LngLo: 2.75, LatHi: 20.4, LngHi: 2.8, CodeLength: 6}},
+		{code: "7FG49QCJ+2V", expectedArea: CodeArea{LatLo: 20.37, LngLo: 2.782125, LatHi: 20.370125, LngHi: 2.78225, CodeLength: 10}},
+		{code: "7fg49qcj+2vx", expectedArea: CodeArea{LatLo: 20.3701, LngLo: 2.78221875, LatHi: 20.370125, LngHi: 2.78225, CodeLength: 11}},
+		{code: "8FVC2222+22", expectedArea: CodeArea{LatLo: 47.0, LngLo: 8.0, LatHi: 47.000125, LngHi: 8.000125, CodeLength: 10}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.code, func(t *testing.T) {
+			area, err := DecodePlusCode(tc.code)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedArea.CodeLength, area.CodeLength)
+			assert.InDelta(t, tc.expectedArea.LatLo, area.LatLo, 1e-10)
+			assert.InDelta(t, tc.expectedArea.LngLo, area.LngLo, 1e-10)
+			assert.InDelta(t, tc.expectedArea.LatHi, area.LatHi, 1e-10)
+			assert.InDelta(t, tc.expectedArea.LngHi, area.LngHi, 1e-10)
+		})
+	}
+}
+
+func TestPlusCode_Validity(t *testing.T) {
+	testCases := []struct {
+		code          PlusCode
+		expectedValid bool
+		expectedFull  bool
+		expectedShort bool
+	}{
+		{code: "8FWC2345+G6", expectedValid: true, expectedFull: true},
+		{code: "8FWC2345+G6G", expectedValid: true, expectedFull: true},
+		{code: "8fwc2345+", expectedValid: true, expectedFull: true},
+		{code: "8FWCX400+", expectedValid: true, expectedFull: true},
+		{code: "WC2345+G6g", expectedValid: true, expectedShort: true},
+		{code: "2345+G6", expectedValid: true, expectedShort: true},
+		{code: "45+G6", expectedValid: true, expectedShort: true},
+		{code: "G+", expectedValid: false},
+		{code: "+", expectedValid: true, expectedShort: true},
+		{code: "8FWC2345+G", expectedValid: false},
+		{code: "8FWC2_45+G6", expectedValid: false},
+		{code: "8FWC2η45+G6", expectedValid: false},
+		{code: "8FWC2345+G6+", expectedValid: false},
+		{code: "8FWC2300+G6", expectedValid: false},
+		{code: "WC2300+G6g", expectedValid: false},
+		{code: "WC2345+G", expectedValid: false},
+		{code: "C2000000+", expectedValid: true, expectedFull: true},
+		{code: "X2000000+", expectedValid: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.code), func(t *testing.T) {
+			assert.Equal(t, tc.expectedValid, tc.code.IsValid())
+			assert.Equal(t, tc.expectedFull, tc.code.IsFull())
+			assert.Equal(t, tc.expectedShort, tc.code.IsShort())
+		})
+	}
+}
+
+func TestRecoverPlusCode(t *testing.T) {
+	testCases := []struct {
+		code         PlusCode
+		reference    LatLng
+		expectedCode PlusCode
+	}{
+		{code: "CJ+2VX", reference: LatLng{Latitude: 51.3701125, Longitude: -1.217765625}, expectedCode: "9C3W9QCJ+2VX"},
+		{code: "9QCJ+2VX", reference: LatLng{Latitude: 51.3708675, Longitude: -1.217765625}, expectedCode: "9C3W9QCJ+2VX"},
+		// The reference is closer to the cells south and west of the one its own digits point at.
+		{code: "XQP5+", reference: LatLng{Latitude: -81.0, Longitude: 0.0}, expectedCode: "2CCXXQP5+"},
+		// The reference is across the antimeridian.
+		{code: "2222+22", reference: LatLng{Latitude: 1, Longitude: 179.9}, expectedCode: "62H22222+22"},
+		{code: "8FVC2222+22", reference: LatLng{Latitude: 0, Longitude: 0}, expectedCode: "8FVC2222+22"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.code), func(t *testing.T) {
+			code, err := RecoverPlusCode(tc.code, tc.reference)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCode, code)
+		})
+	}
+}
+
+func TestFindPlusCode(t *testing.T) {
+	testCases := []struct {
+		text             string
+		expectedCode     PlusCode
+		expectedLocality string
+		expectedStart    int
+		expectedFound    bool
+	}{
+		{text: "8FVC9G8F+6X", expectedCode: "8FVC9G8F+6X", expectedStart: 0, expectedFound: true},
+		{text: "Hala Stulecia, 9G8F+6X Wrocław", expectedCode: "9G8F+6X", expectedLocality: "Wrocław", expectedStart: 15, expectedFound: true},
+		{text: "see 9g8f+6x, Wrocław, Poland", expectedCode: "9G8F+6X", expectedLocality: "Wrocław, Poland", expectedStart: 4, expectedFound: true},
+		{text: "9G8F+6X Wrocław, see you at 5pm", expectedCode: "9G8F+6X", expectedLocality: "Wrocław", expectedStart: 0, expectedFound: true},
+		{text: "Meet at 9G8F+6X Wrocław. Bring snacks", expectedCode: "9G8F+6X", expectedLocality: "Wrocław", expectedStart: 8, expectedFound: true},
+		{text: "9G8F+6X Wrocław, Poland\nSee you there", expectedCode: "9G8F+6X", expectedLocality: "Wrocław, Poland", expectedStart: 0, expectedFound: true},
+		{text: "9G8F+6X, tomorrow at noon", expectedCode: "9G8F+6X", expectedLocality: "", expectedStart: 0, expectedFound: true},
+		{text: "9G8F+6X St. Gallen", expectedCode: "9G8F+6X", expectedLocality: "St. Gallen", expectedStart: 0, expectedFound: true},
+		{text: "9G8F+6X Washington, D.C.", expectedCode: "9G8F+6X", expectedLocality: "Washington, D.C.", expectedStart: 0, expectedFound: true},
+		{text: "C++ and 2+2", expectedStart: -1, expectedFound: false},
+		{text: "no code here", expectedStart: -1, expectedFound: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.text, func(t *testing.T) {
+			code, locality, start, found := FindPlusCode(tc.text)
+
+			assert.Equal(t, tc.expectedFound, found)
+			assert.Equal(t, tc.expectedCode, code)
+			assert.Equal(t, tc.expectedLocality, locality)
+			assert.Equal(t, tc.expectedStart, start)
+		})
+	}
+}
+
+func TestPlusCode_LatLng(t *testing.T) {
+	latLng, err := PlusCode("8FVC2222+22").LatLng()
+	require.NoError(t, err)
+	assert.InDelta(t, 47.0000625, latLng.Latitude, 1e-10)
+	assert.InDelta(t, 8.0000625, latLng.Longitude, 1e-10)
+
+	_, err = PlusCode("2222+22").LatLng()
+	assert.ErrorIs(t, err, ErrInvalidPlusCode)
+}
@@ -0,0 +1,286 @@
+package maps
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrTooManyRedirects is returned when the redirect chain is longer than allowed.
+var ErrTooManyRedirects = errors.New("too many redirects")
+
+// DefaultMaxRedirects is the number of redirects the RedirectResolver follows unless configured otherwise.
+const DefaultMaxRedirects = 10
+
+const (
+	// defaultMaxBodySize caps the part of the final page that is scanned for coordinates.
+	defaultMaxBodySize = 4 << 20
+	// scanChunkSize is the amount of the body read at once while scanning.
+	scanChunkSize = 64 << 10
+	// scanOverlap is the tail of the previous chunk kept so that matches spanning two chunks are found.
+	scanOverlap = 1 << 10
+)
+
+// RedirectResolver resolves links by following their redirect chain hop by hop.
+// Every hop is checked with the URL extractors first, and a page body is downloaded only when none of them matches.
+type RedirectResolver struct {
+	httpClient   *http.Client
+	httpOpts     *httpOpts
+	maxRedirects int
+	maxBodySize  int64
+	budget       Budget
+}
+
+// Budget is the time allowed for each stage of resolving a link. A zero duration leaves the stage unbounded.
+// Extracting the location from the link itself does no I/O and is not bounded.
+type Budget struct {
+	// Redirects bounds following the redirect chain up to the headers of the final page.
+	Redirects time.Duration
+	// Body bounds scanning the body of the final page.
+	Body time.Duration
+}
+
+// DefaultBudget is the Budget of a RedirectResolver unless configured otherwise.
+var DefaultBudget = Budget{
+	Redirects: 10 * time.Second,
+	Body:      10 * time.Second,
+}
+
+// BudgetError is the cause of cancelling a resolution whose stage ran out of its time budget.
+type BudgetError struct {
+	Stage  string
+	Budget time.Duration
+}
+
+func (e *BudgetError) Error() string {
+	return fmt.Sprintf("%s stage exceeded its budget of %s", e.Stage, e.Budget)
+}
+
+// Unwrap makes BudgetError match context.DeadlineExceeded.
+func (e *BudgetError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+const (
+	stageRedirects = "redirects"
+	stageBody      = "body"
+)
+
+// startStage cancels the resolution with a BudgetError once the budget of the stage runs out.
+// The returned function ends the stage and reports whether it finished within its budget.
+func startStage(cancel context.CancelCauseFunc, stage string, budget time.Duration) func() bool {
+	if budget <= 0 {
+		return func() bool { return true }
+	}
+	timer := time.AfterFunc(budget, func() {
+		cancel(&BudgetError{Stage: stage, Budget: budget})
+	})
+	return timer.Stop
+}
+
+// RedirectOpt configures a RedirectResolver.
+type RedirectOpt func(*RedirectResolver)
+
+// WithMaxRedirects limits the number of redirects followed.
+func WithMaxRedirects(n int) RedirectOpt {
+	return func(r *RedirectResolver) {
+		r.maxRedirects = n
+	}
+}
+
+// WithMaxBodySize limits the number of bytes of the final page scanned for coordinates.
+func WithMaxBodySize(n int64) RedirectOpt {
+	return func(r *RedirectResolver) {
+		r.maxBodySize = n
+	}
+}
+
+// WithHttpOpts configures the requests made once the consent page is hit.
+func WithHttpOpts(opts ...HttpOpt) RedirectOpt {
+	return func(r *RedirectResolver) {
+		r.httpOpts = newHttpOpts(opts...)
+	}
+}
+
+// WithBudget sets the time allowed for each stage of resolving a link.
+// Only following the redirects and scanning the page are budgeted. Parsing the link itself has no budget, as it is
+// synchronous work on the URL that no context could interrupt.
+func WithBudget(b Budget) RedirectOpt {
+	return func(r *RedirectResolver) {
+		r.budget = b
+	}
+}
+
+// NewRedirectResolver constructs a RedirectResolver on top of a copy of the given client,
+// which no longer follows redirects on its own.
+func NewRedirectResolver(httpClient *http.Client, opts ...RedirectOpt) *RedirectResolver {
+	client := *httpClient
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	r := &RedirectResolver{
+		httpClient:   &client,
+		httpOpts:     newHttpOpts(),
+		maxRedirects: DefaultMaxRedirects,
+		maxBodySize:  defaultMaxBodySize,
+		budget:       DefaultBudget,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Resolve extracts the GoogleMapsLink from the URL, from any hop of its redirect chain
+// or, as a last resort, from the body of the page the chain ends on.
+// Cancelling the context, or running out of the budget of a stage, aborts the HTTP requests in flight.
+// Links leading to a My Maps map or a saved list fail with a PlaceListError instead.
+func (r *RedirectResolver) Resolve(ctx context.Context, u *url.URL) (*GoogleMapsLink, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	if IsPlaceListURL(u) {
+		return nil, &PlaceListError{URL: u}
+	}
+	if link, ok := googleMapsFromURL(u); ok {
+		return link, nil
+	}
+	hop := u
+	// Links to a place by its identifier are followed to the place page.
+	if id, ok := ParsePlaceIdentifier(u); ok {
+		hop = id.placeURL(u)
+	}
+
+	endRedirects := startStage(cancel, stageRedirects, r.budget.Redirects)
+	resp, err := r.follow(ctx, u, hop)
+	inBudget := endRedirects()
+	if err == nil && resp.link != nil {
+		return resp.link, nil
+	}
+	if !inBudget || ctx.Err() != nil {
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return nil, fmt.Errorf("failed to follow redirects of url: %s, error: %w", u.String(), context.Cause(ctx))
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	endBody := startStage(cancel, stageBody, r.budget.Body)
+	defer endBody()
+	link, err := r.scan(resp.Body)
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("failed to scan page of url: %s, error: %w", u.String(), context.Cause(ctx))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find lat lng for url: %s, error: %w", u.String(), err)
+	}
+	link.route, _ = ParseRoute(resp.Request.URL)
+	return link, nil
+}
+
+// hopResponse is either the link found in a hop of the redirect chain or the response of its final page.
+type hopResponse struct {
+	*http.Response
+	link *GoogleMapsLink
+}
+
+// follow walks the redirect chain from hop, stopping at the first hop carrying a location or at the final page.
+func (r *RedirectResolver) follow(ctx context.Context, u, hop *url.URL) (*hopResponse, error) {
+	var decorate func(*http.Request)
+	for redirects := 0; ; redirects++ {
+		resp, err := httpGet(ctx, r.httpClient, hop, decorate)
+		if err != nil {
+			return nil, err
+		}
+		if !isRedirect(resp.StatusCode) {
+			if resp.StatusCode != http.StatusOK {
+				_ = resp.Body.Close()
+				return nil, fmt.Errorf("request to URL: %s returned non-OK status: %d", hop.String(), resp.StatusCode)
+			}
+			return &hopResponse{Response: resp}, nil
+		}
+
+		next, err := resp.Location()
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read redirect location of url: %s, error: %w", hop.String(), err)
+		}
+		// Skip the consent interstitial, sending the consent cookie from now on.
+		if target, ok := consentTarget(resp); ok {
+			next, decorate = target, r.httpOpts.withConsent
+		}
+		// Lists of places cannot be resolved to a single location, their places are parsed from the list instead.
+		if IsPlaceListURL(next) {
+			return nil, &PlaceListError{URL: next}
+		}
+		if link, ok := googleMapsFromURL(next); ok {
+			return &hopResponse{link: link.withSource(SourceRedirect, redirectPenalty)}, nil
+		}
+		if redirects >= r.maxRedirects {
+			return nil, fmt.Errorf("failed to resolve url: %s, error: %w", u.String(), ErrTooManyRedirects)
+		}
+		hop = next
+	}
+}
+
+func isRedirect(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	default:
+		return false
+	}
+}
+
+// scan streams the body chunk by chunk, collecting the candidates of every chunk up to the size cap and ranking them
+// together, so that a pin further down the page beats a viewport found first. A pin stops the scan early, as nothing
+// found later can beat it.
+func (r *RedirectResolver) scan(body io.Reader) (*GoogleMapsLink, error) {
+	limited := io.LimitReader(body, r.maxBodySize)
+	buf := make([]byte, 0, scanOverlap+scanChunkSize)
+	chunk := make([]byte, scanChunkSize)
+	var links []*GoogleMapsLink
+	name := ""
+	read := 0
+	for {
+		n, readErr := io.ReadFull(limited, chunk)
+		read += n
+		buf = append(buf, chunk[:n]...)
+		window := string(buf)
+		if name == "" {
+			name = placeName(window)
+		}
+		if link, ok := googleMapsFromContent(window); ok {
+			links = append(links, link)
+			if link.candidates[0].Confidence >= confidenceBodyPin {
+				break
+			}
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", readErr)
+		}
+		if len(buf) > scanOverlap {
+			buf = append(buf[:0], buf[len(buf)-scanOverlap:]...)
+		}
+	}
+	link, ok := mergeLinks(links)
+	if !ok {
+		return nil, fmt.Errorf("no coordinates in %d scanned bytes of the page", read)
+	}
+	if link.name == "" {
+		link.name = name
+	}
+	return link, nil
+}
@@ -0,0 +1,197 @@
+package maps
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedirectResolver_Resolve(t *testing.T) {
+	padding := strings.Repeat("x", 3*scanChunkSize-10)
+	var bodyRequests int32
+	httpClient := newRewriteClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Host + r.URL.Path {
+		case "maps.app.goo.gl/pin":
+			http.Redirect(w, r, "https://www.google.com/maps/place/Hala+Stulecia/@51.1,17.0,15z/data=!4m5!3m4!1s0x0:0x1!8m2!3d51.1069402!4d17.0772095", http.StatusFound)
+		case "goo.gl/maps/chain":
+			http.Redirect(w, r, "https://maps.app.goo.gl/intermediate", http.StatusMovedPermanently)
+		case "maps.app.goo.gl/intermediate":
+			http.Redirect(w, r, "https://maps.google.com/maps?q=51.1069402,17.0772095", http.StatusFound)
+		case "maps.app.goo.gl/consent":
+			http.Redirect(w, r, "https://consent.google.com/ml?continue="+url.QueryEscape("https://www.google.com/maps/search/51.1069402,+17.0772095"), http.StatusFound)
+		case "maps.app.goo.gl/body":
+			http.Redirect(w, r, "https://www.google.com/maps/place/Hala+Stulecia", http.StatusFound)
+		case "www.google.com/maps/place/Hala+Stulecia":
+			atomic.AddInt32(&bodyRequests, 1)
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("<title>Hala Stulecia - Google Maps</title>" + padding + "[null,null,51.1069402,17.0772095]"))
+		case "maps.app.goo.gl/loop":
+			http.Redirect(w, r, "https://maps.app.goo.gl/loop", http.StatusFound)
+		default:
+			atomic.AddInt32(&bodyRequests, 1)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	testCases := []struct {
+		name                 string
+		inputURL             string
+		opts                 []RedirectOpt
+		expectedName         string
+		expectedError        string
+		expectedBodyRequests int32
+	}{
+		{name: "Pin in first hop", inputURL: "https://maps.app.goo.gl/pin"},
+		{name: "Coordinates in last hop", inputURL: "https://goo.gl/maps/chain"},
+		{name: "Coordinates in consent continue", inputURL: "https://maps.app.goo.gl/consent"},
+		{
+			name:                 "Coordinates in body",
+			inputURL:             "https://maps.app.goo.gl/body",
+			expectedName:         "Hala Stulecia",
+			expectedBodyRequests: 1,
+		},
+		{
+			name:                 "Coordinates past body size cap",
+			inputURL:             "https://maps.app.goo.gl/body",
+			opts:                 []RedirectOpt{WithMaxBodySize(scanChunkSize)},
+			expectedError:        fmt.Sprintf("no coordinates in %d scanned bytes", scanChunkSize),
+			expectedBodyRequests: 1,
+		},
+		{
+			name:          "Redirect loop",
+			inputURL:      "https://maps.app.goo.gl/loop",
+			opts:          []RedirectOpt{WithMaxRedirects(3)},
+			expectedError: ErrTooManyRedirects.Error(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			atomic.StoreInt32(&bodyRequests, 0)
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			link, err := NewRedirectResolver(httpClient, tc.opts...).Resolve(context.Background(), u)
+			assert.Equal(t, tc.expectedBodyRequests, atomic.LoadInt32(&bodyRequests))
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, LatLng{Latitude: 51.1069402, Longitude: 17.0772095}, link.latLng)
+			assert.Equal(t, tc.expectedName, link.Name())
+		})
+	}
+}
+
+func TestRedirectResolver_ScanPrefersLaterPin(t *testing.T) {
+	// The viewport of the map comes in the first chunk, the pin of the place only in the second one.
+	page := "<title>Hala Stulecia - Google Maps</title>" +
+		`<img src="https://maps.google.com/maps/api/staticmap?center=51.107885%2C17.038538&zoom=15">` +
+		strings.Repeat("x", 2*scanChunkSize) +
+		`<a href="/maps/place/Hala+Stulecia/@51.1069402,17.0772095,17z">`
+	httpClient := newRewriteClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(page))
+	}))
+	u, err := url.Parse("https://www.google.com/maps/place/Hala+Stulecia")
+	require.NoError(t, err)
+
+	link, err := NewRedirectResolver(httpClient).Resolve(context.Background(), u)
+
+	require.NoError(t, err)
+	assert.Equal(t, LatLng{Latitude: 51.1069402, Longitude: 17.0772095}, link.latLng)
+	assert.Equal(t, "Hala Stulecia", link.Name())
+	require.Len(t, link.Candidates(), 2)
+	assert.Equal(t, LatLng{Latitude: 51.107885, Longitude: 17.038538}, link.Candidates()[1].LatLng)
+}
+
+func TestNewRedirectResolver_KeepsClient(t *testing.T) {
+	httpClient := &http.Client{}
+	_ = NewRedirectResolver(httpClient)
+	assert.Nil(t, httpClient.CheckRedirect)
+}
+
+func TestRedirectResolver_Budget(t *testing.T) {
+	// stall blocks the handler until the client gives up on the request.
+	stall := func(r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}
+	httpClient := newRewriteClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow-redirect":
+			stall(r)
+			http.Redirect(w, r, "https://maps.google.com/maps?q=51.1069402,17.0772095", http.StatusFound)
+		case "/slow-body":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("<title>Hala Stulecia - Google Maps</title>"))
+			w.(http.Flusher).Flush()
+			stall(r)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	testCases := []struct {
+		name          string
+		inputURL      string
+		budget        Budget
+		expectedStage string
+	}{
+		{
+			name:          "Redirects out of budget",
+			inputURL:      "https://maps.app.goo.gl/slow-redirect",
+			budget:        Budget{Redirects: 50 * time.Millisecond},
+			expectedStage: stageRedirects,
+		},
+		{
+			name:          "Body scan out of budget",
+			inputURL:      "https://maps.app.goo.gl/slow-body",
+			budget:        Budget{Body: 50 * time.Millisecond},
+			expectedStage: stageBody,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			_, err = NewRedirectResolver(httpClient, WithBudget(tc.budget)).Resolve(context.Background(), u)
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+			var budgetErr *BudgetError
+			require.ErrorAs(t, err, &budgetErr)
+			assert.Equal(t, tc.expectedStage, budgetErr.Stage)
+		})
+	}
+}
+
+func TestRedirectResolver_Cancel(t *testing.T) {
+	started := make(chan struct{})
+	httpClient := newRewriteClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	}))
+	u, err := url.Parse("https://maps.app.goo.gl/hanging")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	_, err = NewRedirectResolver(httpClient).Resolve(ctx, u)
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,72 @@
+package maps
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Parser turns a link of a single map service into its Location.
+type Parser func(ctx context.Context, u *url.URL) (Location, error)
+
+// Registry picks the Parser of a link by its scheme for app links, or by its host for web links.
+// Links of hosts nobody registered go to the fallback, which is the Google Maps resolver in the bot.
+type Registry struct {
+	parsers  map[string]Parser
+	schemes  map[string]Parser
+	fallback Parser
+}
+
+// NewRegistry constructs an empty Registry handing unknown hosts to fallback.
+func NewRegistry(fallback Parser) *Registry {
+	return &Registry{parsers: map[string]Parser{}, schemes: map[string]Parser{}, fallback: fallback}
+}
+
+// RegisterScheme routes links of the given schemes to parser, whatever their host.
+func (r *Registry) RegisterScheme(parser Parser, schemes ...string) {
+	for _, scheme := range schemes {
+		r.schemes[strings.ToLower(scheme)] = parser
+	}
+}
+
+// Register routes links of the given hosts and their subdomains to parser.
+func (r *Registry) Register(parser Parser, hosts ...string) {
+	for _, host := range hosts {
+		r.parsers[normalizeHost(host)] = parser
+	}
+}
+
+// Parse parses the link with the parser registered for its scheme, its host, or the most specific parent domain of it.
+func (r *Registry) Parse(ctx context.Context, u *url.URL) (Location, error) {
+	parser, ok := r.schemes[strings.ToLower(u.Scheme)]
+	if !ok {
+		parser, ok = r.lookup(u.Hostname())
+	}
+	if !ok {
+		if r.fallback == nil {
+			return nil, fmt.Errorf("no parser registered for host: %s", u.Hostname())
+		}
+		parser = r.fallback
+	}
+	return parser(ctx, u)
+}
+
+func (r *Registry) lookup(host string) (Parser, bool) {
+	host = normalizeHost(host)
+	for host != "" {
+		if parser, ok := r.parsers[host]; ok {
+			return parser, true
+		}
+		_, parent, found := strings.Cut(host, ".")
+		if !found {
+			break
+		}
+		host = parent
+	}
+	return nil, false
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
@@ -0,0 +1,56 @@
+package maps
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Parse(t *testing.T) {
+	parser := func(name string) Parser {
+		return func(context.Context, *url.URL) (Location, error) {
+			return &AppleMapsLink{name: name}, nil
+		}
+	}
+	registry := NewRegistry(parser("fallback"))
+	registry.Register(parser("apple"), "maps.apple.com")
+	registry.Register(parser("example"), "Example.COM.")
+	registry.RegisterScheme(parser("app"), "GE0")
+
+	testCases := []struct {
+		inputURL     string
+		expectedName string
+	}{
+		{inputURL: "https://maps.apple.com/?ll=50.0,19.0", expectedName: "apple"},
+		{inputURL: "https://MAPS.APPLE.COM./?ll=50.0,19.0", expectedName: "apple"},
+		{inputURL: "https://www.example.com/map", expectedName: "example"},
+		{inputURL: "https://apple.com/?ll=50.0,19.0", expectedName: "fallback"},
+		{inputURL: "https://maps.google.com/?q=50.0,19.0", expectedName: "fallback"},
+		{inputURL: "ge0://8wAAAAAAAA/Name", expectedName: "app"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.inputURL, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			location, err := registry.Parse(context.Background(), u)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedName, location.(*AppleMapsLink).Name())
+		})
+	}
+}
+
+func TestRegistry_Parse_NoFallback(t *testing.T) {
+	u, err := url.Parse("https://maps.google.com/?q=50.0,19.0")
+	require.NoError(t, err)
+
+	_, err = NewRegistry(nil).Parse(context.Background(), u)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no parser registered for host: maps.google.com")
+}
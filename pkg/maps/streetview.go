@@ -0,0 +1,162 @@
+package maps
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// CameraKind tells which viewer the camera of a link belongs to.
+type CameraKind string
+
+const (
+	// CameraStreetView is the camera of a Street View panorama.
+	CameraStreetView CameraKind = "Street View"
+	// CameraEarth is the camera of a Google Earth view.
+	CameraEarth CameraKind = "Google Earth"
+)
+
+const (
+	earthHost       = "earth.google.com"
+	earthPathPrefix = "/web/@"
+	// panoMapAction is the `map_action=` of the Maps URLs API opening a Street View panorama at `viewpoint=`.
+	panoMapAction = "pano"
+	// streetViewLayer is the `layer=` of the legacy Street View links, the panorama being at `cbll=`.
+	streetViewLayer = "c"
+	// streetViewMode is the value of the `a` field of `@lat,lng,3a,75y,90h,95t` views switching them to Street View.
+	streetViewMode = 3
+	// horizonTilt is the tilt of the `t` field looking at the horizon, 0 looking straight down.
+	horizonTilt = 90
+)
+
+// Camera is where Street View and Google Earth links look from and in which direction.
+type Camera struct {
+	Kind   CameraKind
+	LatLng LatLng
+	// Heading is the compass direction of the view in degrees clockwise from north.
+	Heading float64
+	// Pitch is the angle of the view in degrees above the horizon, negative when looking down.
+	Pitch float64
+}
+
+// URL returns the link opening the same view, the Street View panorama or the Google Earth view.
+func (c Camera) URL() *url.URL {
+	if c.Kind == CameraEarth {
+		path := fmt.Sprintf("%s%.7f,%.7f,0a,1000d,35y,%gh,%gt,0r", earthPathPrefix,
+			c.LatLng.Latitude, c.LatLng.Longitude, c.Heading, c.Pitch+horizonTilt)
+		return &url.URL{Scheme: "https", Host: earthHost, Path: path}
+	}
+	q := url.Values{}
+	q.Set("api", "1")
+	q.Set("map_action", panoMapAction)
+	q.Set("viewpoint", fmt.Sprintf("%.7f,%.7f", c.LatLng.Latitude, c.LatLng.Longitude))
+	q.Set("heading", strconv.FormatFloat(c.Heading, 'f', -1, 64))
+	q.Set("pitch", strconv.FormatFloat(c.Pitch, 'f', -1, 64))
+	return &url.URL{Scheme: "https", Host: googleMapsHost, Path: "/maps/@", RawQuery: q.Encode()}
+}
+
+// extractCamera reads the camera of Street View and Google Earth links, pointing the link at the camera position.
+func extractCamera(u *url.URL) (*GoogleMapsLink, bool) {
+	camera, source, ok := parseCamera(u)
+	if !ok {
+		return nil, false
+	}
+	link := newCandidateLink(newCandidate(camera.LatLng, source, confidencePin, false))
+	link.camera = &camera
+	return link, true
+}
+
+// parseCamera reads the camera of any of the forms Street View and Google Earth links come in:
+// `map_action=pano&viewpoint=lat,lng&heading=90&pitch=5` of the Maps URLs API,
+// `layer=c&cbll=lat,lng&cbp=12,90,,0,-5` of legacy links,
+// `/maps/@lat,lng,3a,75y,90h,95t` of Street View in Maps and `/web/@lat,lng,35a,1000d,35y,90h,45t,0r` of Google Earth.
+func parseCamera(u *url.URL) (Camera, Source, bool) {
+	q := u.Query()
+	switch {
+	case q.Get("map_action") == panoMapAction:
+		latLng, ok := strictLatLng(q.Get("viewpoint"), queryLatLngPattern)
+		if !ok {
+			return Camera{}, "", false
+		}
+		return Camera{
+			Kind:    CameraStreetView,
+			LatLng:  latLng,
+			Heading: normalizeHeading(parseAngle(q.Get("heading"))),
+			Pitch:   parseAngle(q.Get("pitch")),
+		}, SourceQuery, true
+	case q.Get("layer") == streetViewLayer && q.Has("cbll"):
+		latLng, ok := strictLatLng(q.Get("cbll"), queryLatLngPattern)
+		if !ok {
+			return Camera{}, "", false
+		}
+		// `cbp=` holds the frame, the heading, the tilt, the zoom and the pitch, positive when looking down.
+		camera := Camera{Kind: CameraStreetView, LatLng: latLng}
+		cbp := strings.Split(q.Get("cbp"), ",")
+		if len(cbp) > 1 {
+			camera.Heading = normalizeHeading(parseAngle(cbp[1]))
+		}
+		if len(cbp) > 4 {
+			camera.Pitch = -parseAngle(cbp[4])
+		}
+		return camera, SourceQuery, true
+	case strings.EqualFold(u.Hostname(), earthHost) && strings.HasPrefix(u.Path, earthPathPrefix):
+		return parseCameraView(CameraEarth, strings.TrimPrefix(u.Path, earthPathPrefix))
+	case strings.HasPrefix(u.Path, "/maps/") && strings.Contains(u.Path, "/@"):
+		_, view, _ := strings.Cut(u.Path, "/@")
+		return parseCameraView(CameraStreetView, view)
+	}
+	return Camera{}, "", false
+}
+
+// parseCameraView parses the `lat,lng,3a,75y,90h,95t` view following `@`, each field after the coordinates suffixed
+// by its letter. Maps views are only taken in Street View mode, the other ones being the viewport of the map.
+func parseCameraView(kind CameraKind, view string) (Camera, Source, bool) {
+	view, _, _ = strings.Cut(view, "/")
+	parts := strings.Split(view, ",")
+	if len(parts) < 3 {
+		return Camera{}, "", false
+	}
+	latLng, ok := strictLatLng(parts[0]+","+parts[1], routeStopLatLngPattern)
+	if !ok {
+		return Camera{}, "", false
+	}
+	fields := make(map[byte]float64, len(parts)-2)
+	for _, part := range parts[2:] {
+		if len(part) < 2 {
+			continue
+		}
+		v, err := strconv.ParseFloat(part[:len(part)-1], 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		fields[part[len(part)-1]] = v
+	}
+	if mode, ok := fields['a']; kind == CameraStreetView && (!ok || mode != streetViewMode) {
+		return Camera{}, "", false
+	}
+	camera := Camera{Kind: kind, LatLng: latLng, Heading: normalizeHeading(fields['h'])}
+	if tilt, ok := fields['t']; ok {
+		camera.Pitch = tilt - horizonTilt
+	}
+	return camera, SourcePath, true
+}
+
+// parseAngle parses an angle in degrees, taking a missing or malformed one as 0.
+func parseAngle(s string) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0
+	}
+	return v
+}
+
+// normalizeHeading brings the heading into [0, 360).
+func normalizeHeading(heading float64) float64 {
+	heading = math.Mod(heading, 360)
+	if heading < 0 {
+		heading += 360
+	}
+	return heading
+}
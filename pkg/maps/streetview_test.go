@@ -0,0 +1,128 @@
+package maps
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoogleMapsFromURL_Camera(t *testing.T) {
+	testCases := []struct {
+		name           string
+		inputURL       string
+		expectedCamera Camera
+	}{
+		{
+			name:     "Maps URLs API panorama",
+			inputURL: "https://www.google.com/maps/@?api=1&map_action=pano&viewpoint=48.857832,2.295226&heading=-45&pitch=38&fov=80",
+			expectedCamera: Camera{
+				Kind:    CameraStreetView,
+				LatLng:  LatLng{Latitude: 48.857832, Longitude: 2.295226},
+				Heading: 315,
+				Pitch:   38,
+			},
+		},
+		{
+			name:     "Street View path",
+			inputURL: "https://www.google.com/maps/@51.1068963,17.0777458,3a,75y,112.5h,95t/data=!3m6!1e1!3m4!1sAF1QipM!2e0!7i13312!8i6656",
+			expectedCamera: Camera{
+				Kind:    CameraStreetView,
+				LatLng:  LatLng{Latitude: 51.1068963, Longitude: 17.0777458},
+				Heading: 112.5,
+				Pitch:   5,
+			},
+		},
+		{
+			name:     "Street View of a place takes the panorama over the pin",
+			inputURL: "https://www.google.com/maps/place/Hala+Stulecia/@51.1068963,17.0777458,3a,75y,90h,90t/data=!3m7!1e1!4m5!3m4!1s0x470fe9c2d4b58b3f:0x1!8m2!3d51.1069402!4d17.0772095",
+			expectedCamera: Camera{
+				Kind:    CameraStreetView,
+				LatLng:  LatLng{Latitude: 51.1068963, Longitude: 17.0777458},
+				Heading: 90,
+			},
+		},
+		{
+			name:     "Legacy Street View layer",
+			inputURL: "https://maps.google.com/maps?layer=c&cbll=51.1068963,17.0777458&cbp=12,200,,0,10",
+			expectedCamera: Camera{
+				Kind:    CameraStreetView,
+				LatLng:  LatLng{Latitude: 51.1068963, Longitude: 17.0777458},
+				Heading: 200,
+				Pitch:   -10,
+			},
+		},
+		{
+			name:     "Google Earth view",
+			inputURL: "https://earth.google.com/web/@48.8583701,2.2944813,35.5a,1250d,35y,-30h,60t,0r",
+			expectedCamera: Camera{
+				Kind:    CameraEarth,
+				LatLng:  LatLng{Latitude: 48.8583701, Longitude: 2.2944813},
+				Heading: 330,
+				Pitch:   -30,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			link, ok := googleMapsFromURL(u)
+
+			require.True(t, ok)
+			require.NotNil(t, link.Camera())
+			assert.Equal(t, tc.expectedCamera, *link.Camera())
+			assert.Equal(t, tc.expectedCamera.LatLng, link.latLng)
+			assert.False(t, link.Ambiguous())
+		})
+	}
+}
+
+func TestGoogleMapsFromURL_MapViewHasNoCamera(t *testing.T) {
+	for _, inputURL := range []string{
+		"https://www.google.com/maps/@51.107885,17.038538,15z",
+		"https://www.google.com/maps/@51.107885,17.038538,1234m/data=!3m1!1e3",
+		"https://www.google.com/maps/@?api=1&map_action=map&center=51.107885,17.038538",
+	} {
+		u, err := url.Parse(inputURL)
+		require.NoError(t, err)
+
+		link, ok := googleMapsFromURL(u)
+
+		require.True(t, ok, inputURL)
+		assert.Nil(t, link.Camera(), inputURL)
+		assert.Equal(t, LatLng{Latitude: 51.107885, Longitude: 17.038538}, link.latLng, inputURL)
+	}
+}
+
+func TestCamera_URL(t *testing.T) {
+	testCases := []struct {
+		name        string
+		camera      Camera
+		expectedURL string
+	}{
+		{
+			name:        "Street View",
+			camera:      Camera{Kind: CameraStreetView, LatLng: LatLng{Latitude: 51.1068963, Longitude: 17.0777458}, Heading: 112.5, Pitch: 5.3},
+			expectedURL: "https://www.google.com/maps/@?api=1&heading=112.5&map_action=pano&pitch=5.3&viewpoint=51.1068963%2C17.0777458",
+		},
+		{
+			name:        "Google Earth",
+			camera:      Camera{Kind: CameraEarth, LatLng: LatLng{Latitude: 48.8583701, Longitude: 2.2944813}, Heading: 330, Pitch: -30},
+			expectedURL: "https://earth.google.com/web/@48.8583701,2.2944813,0a,1000d,35y,330h,60t,0r",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedURL, tc.camera.URL().String())
+
+			parsed, ok := googleMapsFromURL(tc.camera.URL())
+			require.True(t, ok)
+			assert.Equal(t, tc.camera, *parsed.Camera())
+		})
+	}
+}
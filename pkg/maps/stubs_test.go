@@ -0,0 +1,43 @@
+package maps
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newStubClient constructs a client serving the hand-written responses in
+// `testdata/stubs/<host>/<query-escaped path and query>.http`, so that every stub answers exactly one request.
+// Requests of anything without a stub are answered with 404.
+//
+// The stubs are not recorded from the providers: their codes and identifiers, e.g. the Mapy.cz `gapuhemuza` code and
+// the Bing `osid`, are made up, and their redirect chains follow the documented link formats of HERE, Bing, Mapy.cz and
+// Google Maps. They only check that the parsers follow such chains and read such bodies, not that the providers still
+// answer this way. Stubs replaced by captured responses should keep the status, the Location header and the relevant
+// part of the body, and note the capture date in a header, e.g. `X-Captured: 2026-10-16`.
+func newStubClient(t *testing.T) *http.Client {
+	return newRewriteClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, err := os.Open(filepath.Join("testdata", "stubs", r.Host, url.QueryEscape(r.URL.RequestURI())+".http"))
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		defer f.Close()
+		resp, err := http.ReadResponse(bufio.NewReader(f), r)
+		if err != nil {
+			t.Errorf("failed to read stub of %s: %v", r.URL, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		defer resp.Body.Close()
+		for name, values := range resp.Header {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	}))
+}
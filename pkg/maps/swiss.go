@@ -0,0 +1,83 @@
+package maps
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidLV95 is returned for LV95 coordinates outside of Switzerland.
+var ErrInvalidLV95 = errors.New("invalid lv95 coordinates")
+
+const (
+	// lv95Regex matches an easting and a northing with optional thousands separators, named as LV95 or marked with E and N,
+	// e.g. `LV95 2 600 000 / 1 200 000`, `LV95: 2'600'000, 1'200'000` or `E 2600000.0 N 1200000.0`.
+	lv95Regex = `(?i:\b(LV95)\s*:?\s*)?\b(?:(E)\s*)?(2[ ']?\d{3}[ ']?\d{3}(?:\.\d+)?)\s*[,/;]?\s*(?:(N)\s*)?(1[ ']?\d{3}[ ']?\d{3}(?:\.\d+)?)\b`
+)
+
+var lv95Pattern = regexp.MustCompile(lv95Regex)
+
+// lv95Bounds are the grid coordinates of Switzerland and Liechtenstein with a margin.
+var lv95Bounds = struct{ minE, maxE, minN, maxN float64 }{minE: 2450000, maxE: 2850000, minN: 1050000, maxN: 1320000}
+
+// swissGrid is the LV95 grid, a Swiss oblique Mercator projection of the CH1903+ datum.
+var swissGrid = crs{
+	datum: ch1903PlusDatum,
+	projection: swissObliqueMercator{
+		ellipsoid:     bessel1841,
+		originLat:     46 + 57.0/60 + 8.66/3600,
+		originLng:     7 + 26.0/60 + 22.50/3600,
+		falseEasting:  2600000,
+		falseNorthing: 1200000,
+	},
+}
+
+// LV95 is a location given in the Swiss LV95 grid, e.g. `2 600 000 / 1 200 000` for the old observatory of Bern.
+type LV95 struct {
+	Easting  float64
+	Northing float64
+}
+
+// LatLng returns the WGS84 location of the coordinates.
+func (c LV95) LatLng() (LatLng, error) {
+	if c.Easting < lv95Bounds.minE || c.Easting > lv95Bounds.maxE || c.Northing < lv95Bounds.minN || c.Northing > lv95Bounds.maxN {
+		return LatLng{}, fmt.Errorf("failed to unproject %v: %w", c, ErrInvalidLV95)
+	}
+	return swissGrid.latLng(c.Easting, c.Northing), nil
+}
+
+// LV95FromLatLng projects the WGS84 location into the LV95 grid.
+func LV95FromLatLng(latLng LatLng) LV95 {
+	easting, northing := swissGrid.grid(latLng)
+	return LV95{Easting: easting, Northing: northing}
+}
+
+// FindLV95 finds the first LV95 coordinates within Switzerland in the text, along with the offset they start at.
+// Bare pairs of numbers look like any ticket or phone numbers, only ones named as LV95 or marked with E and N are taken.
+func FindLV95(text string) (LV95, int, bool) {
+	for _, matches := range lv95Pattern.FindAllStringSubmatchIndex(text, -1) {
+		if matches[2] < 0 && (matches[4] < 0 || matches[8] < 0) {
+			continue
+		}
+		easting, err := parseLV95Coordinate(text[matches[6]:matches[7]])
+		if err != nil {
+			continue
+		}
+		northing, err := parseLV95Coordinate(text[matches[10]:matches[11]])
+		if err != nil {
+			continue
+		}
+		c := LV95{Easting: easting, Northing: northing}
+		if _, err := c.LatLng(); err == nil {
+			return c, matches[0], true
+		}
+	}
+	return LV95{}, -1, false
+}
+
+func parseLV95Coordinate(s string) (float64, error) {
+	return strconv.ParseFloat(strings.NewReplacer(" ", "", "'", "").Replace(s), 64)
+}
@@ -0,0 +1,79 @@
+package maps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLV95_LatLng(t *testing.T) {
+	testCases := []struct {
+		name           string
+		lv95           LV95
+		expectedLatLng LatLng
+		expectedError  error
+	}{
+		{
+			// The example of the swisstopo formulas for the Swiss projection.
+			name:           "swisstopo example",
+			lv95:           LV95{Easting: 2700000, Northing: 1100000},
+			expectedLatLng: LatLng{Latitude: dms(46, 2, 38.87), Longitude: dms(8, 43, 49.79)},
+		},
+		{
+			name:           "Old observatory of Bern",
+			lv95:           LV95{Easting: 2600000, Northing: 1200000},
+			expectedLatLng: LatLng{Latitude: dms(46, 57, 3.9), Longitude: dms(7, 26, 19.1)},
+		},
+		{
+			name:          "Outside of Switzerland",
+			lv95:          LV95{Easting: 2000000, Northing: 1200000},
+			expectedError: ErrInvalidLV95,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			latLng, err := tc.lv95.LatLng()
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			// About the metre accuracy of the transformation of the datum.
+			assert.InDelta(t, tc.expectedLatLng.Latitude, latLng.Latitude, 2e-5)
+			assert.InDelta(t, tc.expectedLatLng.Longitude, latLng.Longitude, 2e-5)
+			lv95 := LV95FromLatLng(latLng)
+			assert.InDelta(t, tc.lv95.Easting, lv95.Easting, 1e-2)
+			assert.InDelta(t, tc.lv95.Northing, lv95.Northing, 1e-2)
+		})
+	}
+}
+
+func TestFindLV95(t *testing.T) {
+	testCases := []struct {
+		text          string
+		expectedLV95  LV95
+		expectedStart int
+		expectedOk    bool
+	}{
+		{text: "Treffpunkt LV95 2'600'000 / 1'200'000", expectedLV95: LV95{Easting: 2600000, Northing: 1200000}, expectedStart: 11, expectedOk: true},
+		{text: "E 2 700 000.5 N 1 100 000.25", expectedLV95: LV95{Easting: 2700000.5, Northing: 1100000.25}, expectedStart: 0, expectedOk: true},
+		{text: "lv95: 2700000, 1100000", expectedLV95: LV95{Easting: 2700000, Northing: 1100000}, expectedStart: 0, expectedOk: true},
+		{text: "LV95 2000000, 1100000", expectedStart: -1, expectedOk: false},
+		{text: "ticket 2600000 1200000", expectedStart: -1, expectedOk: false},
+		{text: "E 2600000 1200000", expectedStart: -1, expectedOk: false},
+		{text: "call 2700000", expectedStart: -1, expectedOk: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.text, func(t *testing.T) {
+			lv95, start, ok := FindLV95(tc.text)
+
+			assert.Equal(t, tc.expectedOk, ok)
+			assert.Equal(t, tc.expectedLV95, lv95)
+			assert.Equal(t, tc.expectedStart, start)
+		})
+	}
+}
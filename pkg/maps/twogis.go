@@ -0,0 +1,76 @@
+package maps
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// TwoGISHosts are the hosts 2GIS links are shared from.
+var TwoGISHosts = []string{"2gis.ru", "2gis.com", "2gis.kz", "2gis.ae", "2gis.kg", "2gis.uz"}
+
+const (
+	// twoGISShortLinkHost redirects to the full links.
+	twoGISShortLinkHost = "go.2gis.com"
+	// twoGISPointsPath prefixes the `|`-separated stops of the directions links, each given as `lng,lat;id`.
+	twoGISPointsPath = "/directions/points/"
+)
+
+// twoGISObjectSegments precede the identifier and the `lng,lat` of an object in the path of 2GIS links.
+var twoGISObjectSegments = []string{"geo", "firm"}
+
+// TwoGISLink is a location shared from 2GIS.
+type TwoGISLink struct {
+	latLng LatLng
+}
+
+func (l *TwoGISLink) LatLng() (LatLng, error) {
+	return l.latLng, nil
+}
+
+// ParseTwoGISFromURL extracts TwoGISLink from the `/geo/<id>/lng,lat` and `/firm/<id>/lng,lat` paths of 2GIS links,
+// the destination of their `/directions/points/` routes or the `m=lng,lat/zoom` map center.
+// Short links of `go.2gis.com` are followed to the full link with toTarget.
+func ParseTwoGISFromURL(ctx context.Context, u *url.URL, toTarget UrlToTarget) (*TwoGISLink, error) {
+	if strings.EqualFold(u.Hostname(), twoGISShortLinkHost) {
+		target, err := toTarget(ctx, u)
+		if err != nil {
+			return nil, fmt.Errorf("failed to follow short link: %s, error: %w", u.String(), err)
+		}
+		u = target
+	}
+	if latLng, ok := twoGISPathLatLng(u.Path); ok {
+		return &TwoGISLink{latLng: latLng}, nil
+	}
+	center, _, _ := strings.Cut(u.Query().Get("m"), "/")
+	if latLng, _, ok := lngLatLabel(center); ok {
+		return &TwoGISLink{latLng: latLng}, nil
+	}
+	return nil, fmt.Errorf("failed to find lat lng for url: %s", u.String())
+}
+
+// twoGISPathLatLng finds the coordinates of the object or of the route destination in the path.
+func twoGISPathLatLng(path string) (LatLng, bool) {
+	if _, points, ok := strings.Cut(path, twoGISPointsPath); ok {
+		stops := strings.Split(points, "|")
+		for i := len(stops) - 1; i >= 0; i-- {
+			lngLat, _, _ := strings.Cut(stops[i], ";")
+			if latLng, _, ok := lngLatLabel(lngLat); ok {
+				return latLng, true
+			}
+		}
+		return LatLng{}, false
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i+2 < len(segments); i++ {
+		for _, object := range twoGISObjectSegments {
+			if segments[i] == object {
+				if latLng, _, ok := lngLatLabel(segments[i+2]); ok {
+					return latLng, true
+				}
+			}
+		}
+	}
+	return LatLng{}, false
+}
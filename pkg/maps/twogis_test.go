@@ -0,0 +1,79 @@
+package maps
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTwoGISFromURL(t *testing.T) {
+	httpClient := newRewriteClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host == "go.2gis.com" {
+			http.Redirect(w, r, "https://2gis.ru/moscow/geo/4504235282610451/37.617635,55.755814", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	kremlin := LatLng{Latitude: 55.755814, Longitude: 37.617635}
+
+	testCases := []struct {
+		name           string
+		inputURL       string
+		expectedLatLng LatLng
+		expectedError  string
+	}{
+		{
+			name:           "Object",
+			inputURL:       "https://2gis.ru/moscow/geo/4504235282610451/37.617635,55.755814",
+			expectedLatLng: kremlin,
+		},
+		{
+			name:           "Firm",
+			inputURL:       "https://2gis.ru/moscow/firm/70000001006574545/37.617635%2C55.755814?m=37.6,55.7%2F16",
+			expectedLatLng: kremlin,
+		},
+		{
+			name:           "Directions",
+			inputURL:       "https://2gis.ru/moscow/directions/points/30.315868%2C59.939095%3B5348660212748822%7C37.617635%2C55.755814%3B4504235282610451",
+			expectedLatLng: kremlin,
+		},
+		{
+			name:           "Map center",
+			inputURL:       "https://2gis.kz/almaty?m=37.617635%2C55.755814%2F16",
+			expectedLatLng: kremlin,
+		},
+		{
+			name:           "Short link",
+			inputURL:       "https://go.2gis.com/abc12",
+			expectedLatLng: kremlin,
+		},
+		{
+			name:          "Search without coordinates",
+			inputURL:      "https://2gis.ru/moscow/search/kremlin",
+			expectedError: "failed to find lat lng for url",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			link, err := ParseTwoGISFromURL(context.Background(), u, HttpGetToTarget(httpClient))
+
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			latLng, err := link.LatLng()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedLatLng, latLng)
+		})
+	}
+}
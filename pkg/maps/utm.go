@@ -0,0 +1,176 @@
+package maps
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidUTM is returned for UTM and UPS coordinates with an unknown zone or band.
+var ErrInvalidUTM = errors.New("invalid utm coordinates")
+
+const (
+	utmScale         = 0.9996
+	utmFalseEasting  = 500000.0
+	utmFalseNorthing = 10000000.0
+	// utmSouth and utmNorth are the latitudes UTM covers, the polar regions beyond them are covered by UPS.
+	utmSouth = -80.0
+	utmNorth = 84.0
+	// utmBands are the 8° latitude bands from 80°S up, the last one, X, being 12° tall.
+	utmBands = "CDEFGHJKLMNPQRSTUVWX"
+
+	upsScale = 0.994
+	// upsFalseOrigin is both the false easting and the false northing of UPS, putting the pole at the center of the grid.
+	upsFalseOrigin = 2000000.0
+)
+
+// UTM is a location given in the Universal Transverse Mercator grid, e.g. `33U 412345 5612345`.
+// Zone 0 stands for the Universal Polar Stereographic grid covering the polar regions,
+// with band A or B in the south and Y or Z in the north.
+type UTM struct {
+	Zone     int
+	Band     byte
+	Easting  float64
+	Northing float64
+}
+
+// UTMFromLatLng projects the location into the UTM zone it lies in, or into UPS in the polar regions.
+// The zones widened over Norway and Svalbard take precedence over the regular 6° ones.
+func UTMFromLatLng(latLng LatLng) (UTM, error) {
+	if latLng.Latitude < -90 || latLng.Latitude > 90 {
+		return UTM{}, fmt.Errorf("failed to project latitude %f: %w", latLng.Latitude, ErrInvalidUTM)
+	}
+	lng := normalizeLongitude(latLng.Longitude)
+	if latLng.Latitude < utmSouth || latLng.Latitude >= utmNorth {
+		return upsFromLatLng(LatLng{Latitude: latLng.Latitude, Longitude: lng}), nil
+	}
+	band := int((latLng.Latitude - utmSouth) / 8)
+	if band >= len(utmBands) {
+		band = len(utmBands) - 1
+	}
+	zone := utmZone(latLng.Latitude, lng)
+	easting, northing := utmProjection(zone, latLng.Latitude >= 0).forward(latLng.Latitude, lng)
+	return UTM{Zone: zone, Band: utmBands[band], Easting: easting, Northing: northing}, nil
+}
+
+// utmZone returns the zone of the location, handling the exceptions of south-west Norway and Svalbard.
+func utmZone(lat, lng float64) int {
+	zone := int((lng+180)/6) + 1
+	if zone > 60 {
+		zone = 60
+	}
+	switch {
+	case lat >= 56 && lat < 64 && lng >= 3 && lng < 12:
+		return 32
+	case lat >= 72 && lat < 84 && lng >= 0 && lng < 42:
+		switch {
+		case lng < 9:
+			return 31
+		case lng < 21:
+			return 33
+		case lng < 33:
+			return 35
+		default:
+			return 37
+		}
+	}
+	return zone
+}
+
+// utmProjection returns the projection of the zone, the southern hemisphere having a false northing.
+func utmProjection(zone int, north bool) transverseMercator {
+	p := transverseMercator{
+		ellipsoid:    wgs84,
+		scale:        utmScale,
+		originLng:    float64(zone)*6 - 183,
+		falseEasting: utmFalseEasting,
+	}
+	if !north {
+		p.falseNorthing = utmFalseNorthing
+	}
+	return p
+}
+
+// North tells whether the coordinates are in the northern hemisphere.
+func (u UTM) North() bool {
+	return u.Band >= 'N'
+}
+
+// LatLng returns the location of the coordinates.
+func (u UTM) LatLng() (LatLng, error) {
+	if u.Zone == 0 {
+		if strings.IndexByte("ABYZ", u.Band) < 0 {
+			return LatLng{}, fmt.Errorf("failed to unproject band %c of ups: %w", u.Band, ErrInvalidUTM)
+		}
+		return u.upsLatLng(), nil
+	}
+	if u.Zone < 1 || u.Zone > 60 || strings.IndexByte(utmBands, u.Band) < 0 {
+		return LatLng{}, fmt.Errorf("failed to unproject zone %d%c: %w", u.Zone, u.Band, ErrInvalidUTM)
+	}
+	lat, lng := utmProjection(u.Zone, u.North()).inverse(u.Easting, u.Northing)
+	return LatLng{Latitude: lat, Longitude: lng}, nil
+}
+
+// String formats the coordinates to the metre, e.g. `33U 412345 5612345` or `Z 2000000 2000000` for UPS.
+func (u UTM) String() string {
+	if u.Zone == 0 {
+		return fmt.Sprintf("%c %d %d", u.Band, int(u.Easting), int(u.Northing))
+	}
+	return fmt.Sprintf("%d%c %d %d", u.Zone, u.Band, int(u.Easting), int(u.Northing))
+}
+
+// upsFromLatLng projects a location of the polar regions with the polar stereographic projection.
+func upsFromLatLng(latLng LatLng) UTM {
+	north := latLng.Latitude > 0
+	phi, lambda := math.Abs(latLng.Latitude)*math.Pi/180, latLng.Longitude*math.Pi/180
+	rho := upsRadius(phi)
+	u := UTM{Easting: upsFalseOrigin + rho*math.Sin(lambda)}
+	if north {
+		u.Northing = upsFalseOrigin - rho*math.Cos(lambda)
+	} else {
+		u.Northing = upsFalseOrigin + rho*math.Cos(lambda)
+	}
+	// Bands split the polar regions along the prime meridian.
+	switch {
+	case north && latLng.Longitude < 0:
+		u.Band = 'Y'
+	case north:
+		u.Band = 'Z'
+	case latLng.Longitude < 0:
+		u.Band = 'A'
+	default:
+		u.Band = 'B'
+	}
+	return u
+}
+
+// upsRadius returns the distance from the pole on the grid of the absolute latitude in radians.
+func upsRadius(phi float64) float64 {
+	ecc := wgs84.eccentricity()
+	t := math.Tan(math.Pi/4-phi/2) / math.Pow((1-ecc*math.Sin(phi))/(1+ecc*math.Sin(phi)), ecc/2)
+	return 2 * wgs84.a * upsScale * t / math.Sqrt(math.Pow(1+ecc, 1+ecc)*math.Pow(1-ecc, 1-ecc))
+}
+
+// upsLatLng reverses upsFromLatLng, solving for the latitude iteratively.
+func (u UTM) upsLatLng() LatLng {
+	north := u.Band == 'Y' || u.Band == 'Z'
+	dx, dy := u.Easting-upsFalseOrigin, u.Northing-upsFalseOrigin
+	ecc := wgs84.eccentricity()
+	rho := math.Hypot(dx, dy)
+	t := rho * math.Sqrt(math.Pow(1+ecc, 1+ecc)*math.Pow(1-ecc, 1-ecc)) / (2 * wgs84.a * upsScale)
+	phi := math.Pi/2 - 2*math.Atan(t)
+	for i := 0; i < 10; i++ {
+		phi = math.Pi/2 - 2*math.Atan(t*math.Pow((1-ecc*math.Sin(phi))/(1+ecc*math.Sin(phi)), ecc/2))
+	}
+	lat := phi * 180 / math.Pi
+	var lng float64
+	if north {
+		lng = math.Atan2(dx, -dy) * 180 / math.Pi
+	} else {
+		lat = -lat
+		lng = math.Atan2(dx, dy) * 180 / math.Pi
+	}
+	return LatLng{Latitude: lat, Longitude: lng}
+}
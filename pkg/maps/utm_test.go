@@ -0,0 +1,51 @@
+package maps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUTMFromLatLng(t *testing.T) {
+	testCases := []struct {
+		name        string
+		latLng      LatLng
+		expectedUTM string
+	}{
+		{name: "Equator on the prime meridian", latLng: LatLng{Latitude: 0, Longitude: 0}, expectedUTM: "31N 166021 0"},
+		{name: "Eiffel Tower", latLng: LatLng{Latitude: 48.8582, Longitude: 2.2945}, expectedUTM: "31U 448251 5411932"},
+		{name: "Southern hemisphere", latLng: LatLng{Latitude: -33.8568, Longitude: 151.2153}, expectedUTM: "56H 334900 6252288"},
+		{name: "Norway exception", latLng: LatLng{Latitude: 60, Longitude: 4}, expectedUTM: "32V 221288 6661953"},
+		{name: "Svalbard exception", latLng: LatLng{Latitude: 78, Longitude: 10}, expectedUTM: "33X 384085 8663320"},
+		{name: "North pole", latLng: LatLng{Latitude: 90, Longitude: 0}, expectedUTM: "Z 2000000 2000000"},
+		{name: "South pole", latLng: LatLng{Latitude: -90, Longitude: 0}, expectedUTM: "B 2000000 2000000"},
+		{name: "Arctic west of Greenwich", latLng: LatLng{Latitude: 85, Longitude: -20}, expectedUTM: "Y 1810022 1478040"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := UTMFromLatLng(tc.latLng)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedUTM, u.String())
+			latLng, err := u.LatLng()
+			require.NoError(t, err)
+			assert.InDelta(t, tc.latLng.Latitude, latLng.Latitude, 1e-7)
+			if tc.latLng.Latitude > -90 && tc.latLng.Latitude < 90 {
+				assert.InDelta(t, tc.latLng.Longitude, latLng.Longitude, 1e-7)
+			}
+		})
+	}
+}
+
+func TestUTM_LatLng_Invalid(t *testing.T) {
+	_, err := UTM{Zone: 61, Band: 'U'}.LatLng()
+	assert.ErrorIs(t, err, ErrInvalidUTM)
+
+	_, err = UTM{Zone: 33, Band: 'I'}.LatLng()
+	assert.ErrorIs(t, err, ErrInvalidUTM)
+
+	_, err = UTM{Band: 'U'}.LatLng()
+	assert.ErrorIs(t, err, ErrInvalidUTM)
+}
@@ -0,0 +1,65 @@
+package maps
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// WazeHosts are the hosts Waze links are shared from.
+var WazeHosts = []string{"waze.com"}
+
+const (
+	// wazeLiveMapLatLngPrefix prefixes the coordinates of the `to=` and `from=` parameters of live map links.
+	wazeLiveMapLatLngPrefix = "ll."
+	wazeGeohashPathPrefix   = "/ul/h"
+	// wazeContentLatLngRegex matches the coordinates of a venue in the live map links and the state of Waze pages.
+	wazeContentLatLngRegex = `ll\.(-?\d+\.\d+)(?:%2C|,)(-?\d+\.\d+)|"lat":\s*(-?\d+\.\d+),\s*"lng":\s*(-?\d+\.\d+)`
+)
+
+var wazeContentLatLngPattern = regexp.MustCompile(wazeContentLatLngRegex)
+
+// ParseWazeFromURL extracts WazeLink from the `ul?ll=`, `live-map/directions?to=ll.` and `ul/h<geohash>` forms of Waze links.
+// Links to a venue by its `venue_id=` alone are followed to the venue page with toContent.
+func ParseWazeFromURL(ctx context.Context, u *url.URL, toContent UrlToContent) (*WazeLink, error) {
+	q := u.Query()
+	if latLng, ok := strictLatLng(q.Get("ll"), routeStopLatLngPattern); ok {
+		return &WazeLink{url: u, latLng: latLng}, nil
+	}
+	if to := q.Get("to"); strings.HasPrefix(to, wazeLiveMapLatLngPrefix) {
+		if latLng, ok := strictLatLng(strings.TrimPrefix(to, wazeLiveMapLatLngPrefix), routeStopLatLngPattern); ok {
+			return &WazeLink{url: u, latLng: latLng}, nil
+		}
+	}
+	// Short links carry a geohash prefixed with `h`, e.g. `waze.com/ul/hu3h4fp2x`.
+	if strings.HasPrefix(u.Path, wazeGeohashPathPrefix) {
+		latLng, err := Geohash(strings.TrimPrefix(u.Path, wazeGeohashPathPrefix)).LatLng()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode geohash of url: %s, error: %w", u.String(), err)
+		}
+		return &WazeLink{url: u, latLng: latLng}, nil
+	}
+	if q.Get("venue_id") == "" {
+		return nil, fmt.Errorf("failed to find lat lng for url: %s", u.String())
+	}
+	content, err := toContent(ctx, u)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get content of venue: %s, error: %w", u.String(), err)
+	}
+	matches := wazeContentLatLngPattern.FindStringSubmatch(content)
+	if matches == nil {
+		return nil, fmt.Errorf("failed to find lat lng for venue: %s", q.Get("venue_id"))
+	}
+	// Either the live map link or the state of the page matched.
+	lat, lng := matches[1], matches[2]
+	if lat == "" {
+		lat, lng = matches[3], matches[4]
+	}
+	latLng, ok := strictLatLng(lat+","+lng, routeStopLatLngPattern)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse lat lng of venue: %s", q.Get("venue_id"))
+	}
+	return &WazeLink{url: u, latLng: latLng}, nil
+}
@@ -0,0 +1,111 @@
+package maps
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWazeFromURL(t *testing.T) {
+	httpClient := newRewriteClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/live-map/directions" {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`<link rel="canonical" href="https://www.waze.com/live-map/directions?to=` + url.QueryEscape(r.URL.Query().Get("to")) + `">`))
+			return
+		}
+		switch r.URL.Query().Get("venue_id") {
+		case "2884175.28841750.6310669":
+			http.Redirect(w, r, "https://www.waze.com/live-map/directions?to=ll.51.1069402%2C17.0772095", http.StatusFound)
+		case "2884175.28841750.6310670":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"venue":{"name":"Hala Stulecia","latLng":{"lat": 51.1069402, "lng": 17.0772095}}}`))
+		default:
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("<html><title>Waze</title></html>"))
+		}
+	}))
+
+	testCases := []struct {
+		name           string
+		inputURL       string
+		expectedLatLng LatLng
+		expectedError  string
+	}{
+		{
+			name:           "Navigation link",
+			inputURL:       "https://waze.com/ul?ll=51.1069402,17.0772095&navigate=yes&zoom=17",
+			expectedLatLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095},
+		},
+		{
+			name:           "Live map directions",
+			inputURL:       "https://www.waze.com/live-map/directions?to=ll.51.1069402%2C17.0772095&from=ll.52.2297%2C21.0122",
+			expectedLatLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095},
+		},
+		{
+			name:           "Venue with coordinates",
+			inputURL:       "https://ul.waze.com/ul?venue_id=2884175.28841750.6310669&ll=51.1069402,17.0772095&navigate=yes",
+			expectedLatLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095},
+		},
+		{
+			name:           "Venue redirecting to live map",
+			inputURL:       "https://ul.waze.com/ul?venue_id=2884175.28841750.6310669&overview=yes",
+			expectedLatLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095},
+		},
+		{
+			name:           "Venue page state",
+			inputURL:       "https://ul.waze.com/ul?venue_id=2884175.28841750.6310670&overview=yes",
+			expectedLatLng: LatLng{Latitude: 51.1069402, Longitude: 17.0772095},
+		},
+		{
+			name:           "Geohash short link",
+			inputURL:       "https://waze.com/ul/hu3h4sxepx",
+			expectedLatLng: LatLng{Latitude: 51.10696077346802, Longitude: 17.077195644378662},
+		},
+		{
+			name:          "Unknown venue",
+			inputURL:      "https://ul.waze.com/ul?venue_id=1.1.1",
+			expectedError: "failed to find lat lng for venue: 1.1.1",
+		},
+		{
+			name:          "Live map of a place",
+			inputURL:      "https://www.waze.com/live-map/directions?to=place.ChIJ1234",
+			expectedError: "failed to find lat lng for url",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			link, err := ParseWazeFromURL(context.Background(), u, HttpGetToInput(httpClient))
+
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			latLng, err := link.LatLng()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedLatLng, latLng)
+		})
+	}
+}
+
+func TestGoogleMapsFromLocation(t *testing.T) {
+	waze, err := WazeFromLatLng(LatLng{Latitude: 51.1069402, Longitude: 17.0772095})
+	require.NoError(t, err)
+
+	link, err := GoogleMapsFromLocation(waze)
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://www.google.com/maps/search/?api=1&query=51.1069402%2C17.0772095", link.URL().String())
+	parsed, ok := googleMapsFromURL(link.URL())
+	require.True(t, ok)
+	assert.Equal(t, waze.latLng, parsed.latLng)
+}
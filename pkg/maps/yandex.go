@@ -0,0 +1,87 @@
+package maps
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// YandexHosts are the hosts Yandex Maps and Yandex Navigator links are shared from.
+var YandexHosts = []string{"yandex.ru", "yandex.com", "yandex.by", "yandex.kz", "yandex.uz", "yandex.com.tr"}
+
+// YandexSchemes are the schemes of the Yandex Navigator and Yandex Maps app links.
+var YandexSchemes = []string{"yandexnavi", "yandexmaps"}
+
+const (
+	// yandexShortLinkPath prefixes the short links, e.g. `yandex.ru/maps/-/CCUkrTxN0D`.
+	yandexShortLinkPath = "/maps/-/"
+	// yandexRouteSeparator separates the stops of the `rtext=` route.
+	yandexRouteSeparator = "~"
+)
+
+// YandexLink is a location shared from Yandex Maps or Yandex Navigator.
+type YandexLink struct {
+	latLng LatLng
+	name   string
+}
+
+func (l *YandexLink) LatLng() (LatLng, error) {
+	return l.latLng, nil
+}
+
+// Name returns the searched text when the link carries one.
+func (l *YandexLink) Name() string {
+	return l.name
+}
+
+// ParseYandexFromURL extracts YandexLink from Yandex Maps links, taking the destination of the `rtext=lat,lng~lat,lng`
+// route first, then the `pt=lng,lat` pin, the `whatshere[point]=lng,lat` point and the `ll=lng,lat` map center.
+// Yandex Navigator app links give the destination as `lat_to=` and `lon_to=` or the point as `lat=` and `lon=`.
+// Short links are followed to the full link with toTarget.
+func ParseYandexFromURL(ctx context.Context, u *url.URL, toTarget UrlToTarget) (*YandexLink, error) {
+	if strings.HasPrefix(u.Path, yandexShortLinkPath) {
+		target, err := toTarget(ctx, u)
+		if err != nil {
+			return nil, fmt.Errorf("failed to follow short link: %s, error: %w", u.String(), err)
+		}
+		u = target
+	}
+	q := u.Query()
+	link := &YandexLink{}
+	if text := q.Get("text"); !routeStopLatLngPattern.MatchString(text) {
+		link.name = text
+	}
+	if latLng, ok := yandexRouteDestination(q.Get("rtext")); ok {
+		link.latLng = latLng
+		return link, nil
+	}
+	// Of several pins, separated the same way as route stops, the first one is taken.
+	pin, _, _ := strings.Cut(q.Get("pt"), yandexRouteSeparator)
+	for _, v := range []string{pin, q.Get("whatshere[point]"), q.Get("ll")} {
+		if latLng, _, ok := lngLatLabel(v); ok {
+			link.latLng = latLng
+			return link, nil
+		}
+	}
+	for _, params := range [][2]string{{"lat_to", "lon_to"}, {"lat", "lon"}} {
+		if latLng, ok := strictLatLng(q.Get(params[0])+","+q.Get(params[1]), routeStopLatLngPattern); ok {
+			link.latLng = latLng
+			return link, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to find lat lng for url: %s", u.String())
+}
+
+// yandexRouteDestination returns the last stop of the route given by coordinates, in the `lat,lng` order unlike the pins.
+// Stops left empty stand for the current location.
+func yandexRouteDestination(rtext string) (LatLng, bool) {
+	stops := strings.Split(rtext, yandexRouteSeparator)
+	for i := len(stops) - 1; i >= 0; i-- {
+		if stops[i] == "" {
+			continue
+		}
+		return strictLatLng(stops[i], routeStopLatLngPattern)
+	}
+	return LatLng{}, false
+}
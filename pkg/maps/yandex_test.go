@@ -0,0 +1,98 @@
+package maps
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseYandexFromURL(t *testing.T) {
+	httpClient := newRewriteClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/maps/-/CCUkrTxN0D" {
+			http.Redirect(w, r, "https://yandex.ru/maps/213/moscow/?ll=37.620070%2C55.753630&mode=whatshere&whatshere%5Bpoint%5D=37.617635%2C55.755814&whatshere%5Bzoom%5D=17&z=17", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	kremlin := LatLng{Latitude: 55.755814, Longitude: 37.617635}
+
+	testCases := []struct {
+		name           string
+		inputURL       string
+		expectedLatLng LatLng
+		expectedName   string
+		expectedError  string
+	}{
+		{
+			// Both values are valid latitudes, so only the order of the parameter tells them apart.
+			name:           "Map center",
+			inputURL:       "https://yandex.ru/maps/?ll=37.617635%2C55.755814&z=17",
+			expectedLatLng: kremlin,
+		},
+		{
+			name:           "Pins",
+			inputURL:       "https://yandex.ru/maps/?ll=37.6,55.7&pt=37.617635,55.755814,pm2rdm~30.315868,59.939095&z=12",
+			expectedLatLng: kremlin,
+		},
+		{
+			name:           "Route",
+			inputURL:       "https://yandex.ru/maps/?rtext=59.939095%2C30.315868~55.755814%2C37.617635&rtt=auto",
+			expectedLatLng: kremlin,
+		},
+		{
+			name:           "Route from the current location",
+			inputURL:       "https://yandex.com/maps/?rtext=~55.755814%2C37.617635&rtt=auto",
+			expectedLatLng: kremlin,
+		},
+		{
+			name:           "Search",
+			inputURL:       "https://yandex.ru/maps/?ll=37.617635,55.755814&text=Kremlin&z=16",
+			expectedLatLng: kremlin,
+			expectedName:   "Kremlin",
+		},
+		{
+			name:           "Short link",
+			inputURL:       "https://yandex.ru/maps/-/CCUkrTxN0D",
+			expectedLatLng: kremlin,
+		},
+		{
+			name:           "Navigator route",
+			inputURL:       "yandexnavi://build_route_on_map?lat_to=55.755814&lon_to=37.617635",
+			expectedLatLng: kremlin,
+		},
+		{
+			name:           "Navigator point",
+			inputURL:       "yandexnavi://show_point_on_map?lat=55.755814&lon=37.617635&zoom=12",
+			expectedLatLng: kremlin,
+		},
+		{
+			name:          "Organization without coordinates",
+			inputURL:      "https://yandex.ru/maps/org/kremlin/1023322799/",
+			expectedError: "failed to find lat lng for url",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.inputURL)
+			require.NoError(t, err)
+
+			link, err := ParseYandexFromURL(context.Background(), u, HttpGetToTarget(httpClient))
+
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			latLng, err := link.LatLng()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedLatLng, latLng)
+			assert.Equal(t, tc.expectedName, link.Name())
+		})
+	}
+}
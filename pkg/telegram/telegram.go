@@ -1,6 +1,7 @@
 package telegram
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/url"
@@ -10,11 +11,14 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// MaxMessageLength is the most characters Telegram takes in a single message, counted in UTF-16 code units.
+const MaxMessageLength = 4096
+
 // Client is an interface for interacting with the Telegram API.
 type Client interface {
 	Webhook(domain *url.URL, f OnMessage) (*Webhook, error)
 	CloseWebhook() error
-	Poll(f OnMessage) error
+	Poll(ctx context.Context, f OnMessage) error
 }
 
 // Message represents a message received from Telegram.
@@ -34,7 +38,8 @@ type Reply struct {
 }
 
 // OnMessage is a function that is called for each message received.
-type OnMessage func(msg *Message) error
+// The context is done once the message no longer needs processing, e.g. when the webhook request ends.
+type OnMessage func(ctx context.Context, msg *Message) error
 
 type clientImpl struct {
 	bot *tgbotapi.BotAPI
@@ -90,7 +95,7 @@ func (c *clientImpl) handler(f OnMessage) func(w http.ResponseWriter, r *http.Re
 			return
 		}
 		msg := c.message(update)
-		err = f(msg)
+		err = f(r.Context(), msg)
 		if err != nil {
 			log.Errorf("failed to process message: %v", err)
 			err = msg.Reply(&Reply{
@@ -138,20 +143,29 @@ func (c *clientImpl) CloseWebhook() error {
 }
 
 // Poll starts polling for messages and calls the given function f for each message received.
-func (c *clientImpl) Poll(f OnMessage) error {
+// Polling stops once the context is done.
+func (c *clientImpl) Poll(ctx context.Context, f OnMessage) error {
 	ch := c.bot.GetUpdatesChan(tgbotapi.UpdateConfig{})
-	for update := range ch {
-		msg := c.message(&update)
-		err := f(msg)
-		if err != nil {
-			log.Errorf("failed to process message: %v", err)
-			err = msg.Reply(&Reply{
-				Text: "Try again",
-			})
+	for {
+		select {
+		case <-ctx.Done():
+			c.bot.StopReceivingUpdates()
+			return ctx.Err()
+		case update, ok := <-ch:
+			if !ok {
+				return errors.New("failed to receive updates")
+			}
+			msg := c.message(&update)
+			err := f(ctx, msg)
 			if err != nil {
-				log.Errorf("failed to reply to message: %v", err)
+				log.Errorf("failed to process message: %v", err)
+				err = msg.Reply(&Reply{
+					Text: "Try again",
+				})
+				if err != nil {
+					log.Errorf("failed to reply to message: %v", err)
+				}
 			}
 		}
 	}
-	return errors.New("failed to receive updates")
 }
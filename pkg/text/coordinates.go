@@ -0,0 +1,200 @@
+package text
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pawel-ochrymowicz/google-maps-to-waze/pkg/maps"
+	"github.com/pkg/errors"
+)
+
+// ErrNoCoordinates is returned when the text holds no coordinates in any of the known notations.
+var ErrNoCoordinates = errors.New("no coordinates found in text")
+
+const (
+	// coordinateRegex matches a single coordinate in decimal degrees, e.g. `51.1079°`,
+	// degrees and decimal minutes, e.g. `51 6.473`, or degrees, minutes and seconds, e.g. `51°06'28.4"`.
+	coordinateRegex = `([-+]?)\s*(?:(\d{1,3}[.,]\d+)\s*°?|(\d{1,3})\s*°?(?:\s*(\d{1,2}(?:[.,]\d+)?)\s*'?(?:\s*(\d{1,2}(?:[.,]\d+)?)\s*"?)?)?)`
+	hemisphereRegex = `([NSEW])`
+	// coordinatesSeparatorRegex separates latitude and longitude, commas being told apart from decimal commas by backtracking.
+	coordinatesSeparatorRegex = `\s*[,;/]?\s*`
+	coordinatesStartRegex     = `(?:^|[^\pL\d.,+-])`
+	coordinatesEndRegex       = `(?:$|[^\pL\d])`
+	// minUnmarkedDecimals is the number of decimal places of both coordinates of a pair given without hemisphere letters,
+	// symbols or signs, below which it looks more like prices or version numbers, e.g. `12.50, 13.99`.
+	minUnmarkedDecimals = 3
+)
+
+var (
+	// coordinatesPatterns match the hemisphere letters given before the numbers, e.g. `N 51 6.473 E 17 2.312`,
+	// or after them, e.g. `51°06'28.4"N 17°02'18.7"E`, or not at all, e.g. `51.1079, 17.0385`.
+	coordinatesPatterns = []*regexp.Regexp{
+		regexp.MustCompile(coordinatesStartRegex +
+			hemisphereRegex + `\s*` + coordinateRegex + `()` + coordinatesSeparatorRegex +
+			hemisphereRegex + `\s*` + coordinateRegex + `()` + coordinatesEndRegex),
+		regexp.MustCompile(coordinatesStartRegex +
+			`()` + coordinateRegex + `(?:\s*` + hemisphereRegex + `)?` + coordinatesSeparatorRegex +
+			`()` + coordinateRegex + `(?:\s*` + hemisphereRegex + `)?` + coordinatesEndRegex),
+	}
+	// coordinateSymbolsReplacer brings the look-alike symbols down to `°`, `'` and `"`.
+	coordinateSymbolsReplacer = strings.NewReplacer(
+		"″", `"`, "”", `"`, "“", `"`, "''", `"`,
+		"′", "'", "’", "'", "‘", "'", "´", "'",
+		"º", "°", "˚", "°",
+		"−", "-",
+	)
+)
+
+// coordinate is a single parsed coordinate, signed by the sign or the hemisphere it was given with.
+type coordinate struct {
+	value      float64
+	hemisphere string
+	// decimals is the number of decimal places of decimal degrees, zero for degrees given with minutes or seconds.
+	decimals int
+	// signed tells whether the coordinate was given with a sign.
+	signed bool
+}
+
+// ParseCoordinates parses the first latitude and longitude pair found in the text.
+// Decimal degrees, degrees with decimal minutes and degrees with minutes and seconds are recognised,
+// with hemisphere letters before or after the numbers, degree symbols and commas as decimal separators.
+// Pairs given without hemisphere letters, symbols or signs are only taken in decimal degrees precise to 3 decimal places,
+// or when they are the whole text, as they are more likely prices, times or version numbers.
+// Signs count only at the start of a number, as `3-2` or `10-20` are scores and ranges.
+func ParseCoordinates(text string) (maps.LatLng, error) {
+	latLng, _, ok := findCoordinates(coordinateSymbolsReplacer.Replace(text))
+	if !ok {
+		return maps.LatLng{}, ErrNoCoordinates
+	}
+	return latLng, nil
+}
+
+// findCoordinates finds the first latitude and longitude pair in the text with the symbols already replaced,
+// along with the offset it starts at.
+func findCoordinates(text string) (maps.LatLng, int, bool) {
+	best, found := -1, maps.LatLng{}
+	for _, pattern := range coordinatesPatterns {
+		for _, match := range pattern.FindAllStringSubmatchIndex(text, -1) {
+			// The first group starts where the coordinates do, past the character preceding them.
+			if best >= 0 && match[2] >= best {
+				break
+			}
+			if latLng, ok := coordinatesFromMatch(text, match); ok {
+				best, found = match[2], latLng
+				break
+			}
+		}
+	}
+	return found, best, best >= 0
+}
+
+func coordinatesFromMatch(text string, match []int) (maps.LatLng, bool) {
+	group := func(i int) string {
+		if match[2*i] < 0 {
+			return ""
+		}
+		return text[match[2*i]:match[2*i+1]]
+	}
+	// Groups of a coordinate: the prefix hemisphere, the sign, decimal degrees, degrees, minutes, seconds and the suffix hemisphere.
+	first, ok := parseCoordinate(group(1), group(2), group(3), group(4), group(5), group(6), group(7))
+	if !ok {
+		return maps.LatLng{}, false
+	}
+	second, ok := parseCoordinate(group(8), group(9), group(10), group(11), group(12), group(13), group(14))
+	if !ok {
+		return maps.LatLng{}, false
+	}
+	// Signs right after a digit join ranges and scores rather than start a coordinate, e.g. `pages 10-20` or `3-2`.
+	for _, sign := range []int{2, 9} {
+		if at := match[2*sign]; at > 0 && at < match[2*sign+1] && isDigit(text[at-1]) {
+			return maps.LatLng{}, false
+		}
+	}
+	// The pair spans from the first group to the furthest end of a group, short of the character following it.
+	start, end := match[2], match[3]
+	for i := 3; i < len(match); i += 2 {
+		if match[i] > end {
+			end = match[i]
+		}
+	}
+	marked := first.hemisphere != "" || second.hemisphere != "" || strings.ContainsAny(text[start:end], `°'"`)
+	if !marked && !unmarkedCoordinates(first, second, strings.TrimSpace(text) == strings.TrimSpace(text[start:end])) {
+		return maps.LatLng{}, false
+	}
+	lat, lng := first, second
+	if isLongitudeHemisphere(first.hemisphere) || isLatitudeHemisphere(second.hemisphere) {
+		lat, lng = second, first
+	}
+	if isLongitudeHemisphere(lat.hemisphere) || isLatitudeHemisphere(lng.hemisphere) {
+		return maps.LatLng{}, false
+	}
+	latLng := maps.LatLng{Latitude: lat.value, Longitude: lng.value}
+	if !latLng.Valid() {
+		return maps.LatLng{}, false
+	}
+	return latLng, true
+}
+
+// unmarkedCoordinates tells whether a pair given without hemisphere letters or symbols is taken as coordinates.
+// Only decimal degrees are, as bare integers read as degrees and minutes look like times, e.g. `12 30, 13 45`,
+// and signed integers look like anything else, e.g. `-5, 10`. Signed decimals are taken at any precision.
+func unmarkedCoordinates(first, second coordinate, whole bool) bool {
+	if first.decimals == 0 || second.decimals == 0 {
+		return false
+	}
+	if whole || first.signed || second.signed {
+		return true
+	}
+	return first.decimals >= minUnmarkedDecimals && second.decimals >= minUnmarkedDecimals
+}
+
+func parseCoordinate(prefix, sign, decimal, degrees, minutes, seconds, suffix string) (coordinate, bool) {
+	if prefix != "" && suffix != "" {
+		return coordinate{}, false
+	}
+	c := coordinate{hemisphere: prefix + suffix, signed: sign != ""}
+	if decimal != "" {
+		degrees = decimal
+		c.decimals = len(decimal) - strings.IndexAny(decimal, ".,") - 1
+	}
+	value, err := parseDecimal(degrees)
+	if err != nil {
+		return coordinate{}, false
+	}
+	for i, part := range []string{minutes, seconds} {
+		if part == "" {
+			break
+		}
+		v, err := parseDecimal(part)
+		if err != nil || v >= 60 {
+			return coordinate{}, false
+		}
+		value += v / []float64{60, 3600}[i]
+	}
+	switch {
+	case sign == "-" && c.hemisphere != "":
+		// A negative value in a named hemisphere is contradictory.
+		return coordinate{}, false
+	case sign == "-" || c.hemisphere == "S" || c.hemisphere == "W":
+		value = -value
+	}
+	c.value = value
+	return c, true
+}
+
+func parseDecimal(s string) (float64, error) {
+	return strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLatitudeHemisphere(h string) bool {
+	return h == "N" || h == "S"
+}
+
+func isLongitudeHemisphere(h string) bool {
+	return h == "E" || h == "W"
+}
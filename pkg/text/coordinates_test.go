@@ -0,0 +1,73 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/pawel-ochrymowicz/google-maps-to-waze/pkg/maps"
+)
+
+func TestParseCoordinates(t *testing.T) {
+	testCases := []struct {
+		name           string
+		text           string
+		expectedLatLng maps.LatLng
+		expectedError  error
+	}{
+		{name: "Decimal", text: "51.1079, 17.0385", expectedLatLng: maps.LatLng{Latitude: 51.1079, Longitude: 17.0385}},
+		{name: "Decimal without separator", text: "meet at 51.1079 17.0385 at noon", expectedLatLng: maps.LatLng{Latitude: 51.1079, Longitude: 17.0385}},
+		{name: "Decimal negative", text: "-33.8688,151.2093", expectedLatLng: maps.LatLng{Latitude: -33.8688, Longitude: 151.2093}},
+		{name: "Decimal with comma separators", text: "51,1079; 17,0385", expectedLatLng: maps.LatLng{Latitude: 51.1079, Longitude: 17.0385}},
+		{name: "Decimal commas and comma separator", text: "51,1079, 17,0385", expectedLatLng: maps.LatLng{Latitude: 51.1079, Longitude: 17.0385}},
+		{name: "Decimal with degrees and hemispheres", text: "51.1079° N, 17.0385° W", expectedLatLng: maps.LatLng{Latitude: 51.1079, Longitude: -17.0385}},
+		{name: "Decimal with hemispheres first", text: "S 33.8688 E 151.2093", expectedLatLng: maps.LatLng{Latitude: -33.8688, Longitude: 151.2093}},
+		{name: "DMS", text: `51°06'28.4"N 17°02'18.7"E`, expectedLatLng: maps.LatLng{Latitude: 51 + 6.0/60 + 28.4/3600, Longitude: 17 + 2.0/60 + 18.7/3600}},
+		{name: "DMS with primes", text: "Hala: 51°06′28.4″N, 17°02′18.7″E", expectedLatLng: maps.LatLng{Latitude: 51 + 6.0/60 + 28.4/3600, Longitude: 17 + 2.0/60 + 18.7/3600}},
+		{name: "DMS without symbols", text: "51 06 28.4 S 17 02 18.7 W", expectedLatLng: maps.LatLng{Latitude: -(51 + 6.0/60 + 28.4/3600), Longitude: -(17 + 2.0/60 + 18.7/3600)}},
+		{name: "DMS longitude first", text: `17°02'18.7"E 51°06'28.4"N`, expectedLatLng: maps.LatLng{Latitude: 51 + 6.0/60 + 28.4/3600, Longitude: 17 + 2.0/60 + 18.7/3600}},
+		{name: "DDM", text: "N 51 6.473 E 17 2.312", expectedLatLng: maps.LatLng{Latitude: 51 + 6.473/60, Longitude: 17 + 2.312/60}},
+		{name: "DDM with symbols", text: "N51°6.473' E017°2.312'", expectedLatLng: maps.LatLng{Latitude: 51 + 6.473/60, Longitude: 17 + 2.312/60}},
+		{name: "Suffix is not a word", text: "51.1079 17.0385 Street", expectedLatLng: maps.LatLng{Latitude: 51.1079, Longitude: 17.0385}},
+		{name: "Imprecise decimals alone", text: "12.50, 13.99", expectedLatLng: maps.LatLng{Latitude: 12.5, Longitude: 13.99}},
+		{name: "Imprecise decimals with sign", text: "meet at -33.87, 151.21 then", expectedLatLng: maps.LatLng{Latitude: -33.87, Longitude: 151.21}},
+		{name: "Signed decimals after a space", text: "at 51.1079 -0.1278 now", expectedLatLng: maps.LatLng{Latitude: 51.1079, Longitude: -0.1278}},
+		{name: "Integers", text: "room 12, 34", expectedError: ErrNoCoordinates},
+		{name: "Prices", text: "price 12.50, 13.99", expectedError: ErrNoCoordinates},
+		{name: "Version numbers", text: "version 1.2, 3.4 released", expectedError: ErrNoCoordinates},
+		{name: "Times", text: "meet at 12 30, 13 45", expectedError: ErrNoCoordinates},
+		{name: "Times alone", text: "12 30, 13 45", expectedError: ErrNoCoordinates},
+		{name: "Scores", text: "score 3-2, 1-0", expectedError: ErrNoCoordinates},
+		{name: "Page range", text: "pages 10-20", expectedError: ErrNoCoordinates},
+		{name: "Opening hours", text: "open 9-17 daily", expectedError: ErrNoCoordinates},
+		{name: "Room number", text: "room 12-34", expectedError: ErrNoCoordinates},
+		{name: "Chapter ranges", text: "chapters 5-7, 9-11", expectedError: ErrNoCoordinates},
+		{name: "Price range", text: "12.50-13.99", expectedError: ErrNoCoordinates},
+		{name: "Signed integers", text: "moved -5, 10 places", expectedError: ErrNoCoordinates},
+		{name: "One imprecise decimal", text: "at 51.1079, 17.03 maybe", expectedError: ErrNoCoordinates},
+		{name: "Latitude out of range", text: "91.5, 17.0385", expectedError: ErrNoCoordinates},
+		{name: "Longitude out of range", text: "51.1079, 181.5", expectedError: ErrNoCoordinates},
+		{name: "Minutes out of range", text: "N 51 60.5 E 17 2.312", expectedError: ErrNoCoordinates},
+		{name: "Two latitudes", text: "51.1079 N 17.0385 S", expectedError: ErrNoCoordinates},
+		{name: "Negative with hemisphere", text: "-51.1079 N 17.0385 E", expectedError: ErrNoCoordinates},
+		{name: "No coordinates", text: "Try again", expectedError: ErrNoCoordinates},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			latLng, err := ParseCoordinates(tc.text)
+
+			if err != tc.expectedError {
+				t.Fatalf("Expected error %v but got %v", tc.expectedError, err)
+			}
+			if tc.expectedError != nil {
+				return
+			}
+			if !closeTo(latLng.Latitude, tc.expectedLatLng.Latitude) || !closeTo(latLng.Longitude, tc.expectedLatLng.Longitude) {
+				t.Errorf("Expected %v but got %v", tc.expectedLatLng, latLng)
+			}
+		})
+	}
+}
+
+func closeTo(a, b float64) bool {
+	return a-b < 1e-9 && b-a < 1e-9
+}
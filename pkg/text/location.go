@@ -0,0 +1,52 @@
+package text
+
+import (
+	"fmt"
+
+	"github.com/pawel-ochrymowicz/google-maps-to-waze/pkg/maps"
+)
+
+// ShortPlusCodeError is returned when the location given first in the text is a short plus code, e.g. `9G8F+6X Wrocław`.
+// The code is recovered with maps.RecoverPlusCode from the location of the locality following it, empty when none does.
+type ShortPlusCodeError struct {
+	Code     maps.PlusCode
+	Locality string
+}
+
+func (e *ShortPlusCodeError) Error() string {
+	return fmt.Sprintf("short plus code relative to a locality: %s %s", e.Code, e.Locality)
+}
+
+// gridFinders find a location given in one of the grids and notations in the text, along with the offset it starts at.
+var gridFinders = []func(text string) (maps.Location, int, bool){
+	func(text string) (maps.Location, int, bool) { return maps.FindMGRS(text) },
+	func(text string) (maps.Location, int, bool) { return maps.FindOSGridRef(text) },
+	func(text string) (maps.Location, int, bool) { return maps.FindLV95(text) },
+	func(text string) (maps.Location, int, bool) { return maps.FindGeohash(text) },
+	func(text string) (maps.Location, int, bool) { return maps.FindMaidenhead(text) },
+}
+
+// FindLocation finds the location given first in the text, be it a plus code, a grid reference, a geohash, a locator
+// or coordinates. Short plus codes fail with a ShortPlusCodeError instead.
+func FindLocation(text string) (maps.Location, error) {
+	text = coordinateSymbolsReplacer.Replace(text)
+	best, found := -1, maps.Location(nil)
+	for _, find := range gridFinders {
+		if location, start, ok := find(text); ok && (best < 0 || start < best) {
+			best, found = start, location
+		}
+	}
+	if latLng, start, ok := findCoordinates(text); ok && (best < 0 || start < best) {
+		best, found = start, latLng
+	}
+	if code, locality, start, ok := maps.FindPlusCode(text); ok && (best < 0 || start < best) {
+		if code.IsShort() {
+			return nil, &ShortPlusCodeError{Code: code, Locality: locality}
+		}
+		best, found = start, code
+	}
+	if best < 0 {
+		return nil, ErrNoCoordinates
+	}
+	return found, nil
+}
@@ -0,0 +1,71 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/pawel-ochrymowicz/google-maps-to-waze/pkg/maps"
+)
+
+func TestFindLocation(t *testing.T) {
+	testCases := []struct {
+		name             string
+		text             string
+		expectedLocation maps.Location
+		expectedError    error
+	}{
+		{name: "Grid-looking gate before coordinates", text: "Gate SH 12 34 at 52.2297, 21.0122", expectedLocation: maps.LatLng{Latitude: 52.2297, Longitude: 21.0122}},
+		{name: "Grid-looking room before coordinates", text: "Room TL 10 20, coords 50.061, 19.937", expectedLocation: maps.LatLng{Latitude: 50.061, Longitude: 19.937}},
+		{name: "Ticket numbers before coordinates", text: "ticket 2600000 1200000, meet at 46.948, 7.4474", expectedLocation: maps.LatLng{Latitude: 46.948, Longitude: 7.4474}},
+		{name: "Coordinates before grid reference", text: "51.1079, 17.0385 or TQ 30080 80001", expectedLocation: maps.LatLng{Latitude: 51.1079, Longitude: 17.0385}},
+		{name: "Grid reference before coordinates", text: "TQ 30080 80001 or 51.1079, 17.0385", expectedLocation: maps.OSGridRef("TQ 30080 80001")},
+		{name: "Locator before coordinates", text: "QTH JO81ld, home 51.1079, 17.0385", expectedLocation: maps.Maidenhead("JO81ld")},
+		{name: "LV95 after coordinates with primes", text: "51°06′28.4″N, 17°02′18.7″E then LV95 2 600 000 / 1 200 000", expectedLocation: maps.LatLng{Latitude: 51 + 6.0/60 + 28.4/3600, Longitude: 17 + 2.0/60 + 18.7/3600}},
+		{name: "Coordinates before plus code", text: "meet at 52.2297, 21.0122, not 9C4XGV4M+XX", expectedLocation: maps.LatLng{Latitude: 52.2297, Longitude: 21.0122}},
+		{name: "Plus code before coordinates", text: "meet at 9C4XGV4M+XX, not 52.2297, 21.0122", expectedLocation: maps.PlusCode("9C4XGV4M+XX")},
+		{name: "No location", text: "Gate SH 12 34, ticket 2600000 1200000", expectedError: ErrNoCoordinates},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			location, err := FindLocation(tc.text)
+
+			if err != tc.expectedError {
+				t.Fatalf("Expected error %v but got %v", tc.expectedError, err)
+			}
+			if tc.expectedError != nil {
+				return
+			}
+			latLng, ok := location.(maps.LatLng)
+			expectedLatLng, expectedOk := tc.expectedLocation.(maps.LatLng)
+			if ok && expectedOk {
+				if !closeTo(latLng.Latitude, expectedLatLng.Latitude) || !closeTo(latLng.Longitude, expectedLatLng.Longitude) {
+					t.Errorf("Expected %v but got %v", expectedLatLng, latLng)
+				}
+				return
+			}
+			if location != tc.expectedLocation {
+				t.Errorf("Expected %v but got %v", tc.expectedLocation, location)
+			}
+		})
+	}
+}
+
+func TestFindLocation_ShortPlusCode(t *testing.T) {
+	_, err := FindLocation("meet at 9G8F+6X Wrocław, not 52.2297, 21.0122")
+
+	shortErr, ok := err.(*ShortPlusCodeError)
+	if !ok {
+		t.Fatalf("Expected a ShortPlusCodeError but got %v", err)
+	}
+	if shortErr.Code != "9G8F+6X" || shortErr.Locality != "Wrocław" {
+		t.Errorf("Expected 9G8F+6X in Wrocław but got %s in %s", shortErr.Code, shortErr.Locality)
+	}
+
+	location, err := FindLocation("meet at 52.2297, 21.0122, not 9G8F+6X Wrocław")
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	if location != (maps.LatLng{Latitude: 52.2297, Longitude: 21.0122}) {
+		t.Errorf("Expected the coordinates but got %v", location)
+	}
+}
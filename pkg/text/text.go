@@ -7,7 +7,18 @@ import (
 )
 
 const (
-	urlRegex = "(http|ftp|https):\\/\\/([\\w_-]+(?:(?:\\.[\\w_-]+)+))([\\w.,@?^=%&:\\/~+#-]*[\\w@?^=%&\\/~+#-])"
+	// Paths and queries may carry `|`, `;` and `[]`, e.g. the route stops of 2GIS and the `whatshere[point]=` of Yandex,
+	// as well as the `!` and `*` of the Google Maps `data=` parameter. None of `!`, `*` and `]` ends a link, as they are more
+	// likely punctuation or markdown around it, e.g. `**https://maps.app.goo.gl/AbCd123**`.
+	urlRegex = "(http|ftp|https):\\/\\/([\\w_-]+(?:(?:\\.[\\w_-]+)+))([\\w.,;@?^=%&:\\/~+#|!*\\[\\]-]*[\\w@?^=%&\\/~+#|-])" +
+		// Organic Maps app links carry their code in place of the host, e.g. `ge0://8wAAAAAAAA/Name`.
+		"|ge0:\\/\\/[\\w-]{10}(?:\\/[\\w.,@?^=%&:~+#-]*[\\w@?^=%&~+#-])?" +
+		// Yandex app links, e.g. `yandexnavi://build_route_on_map?lat_to=55.75&lon_to=37.61`.
+		"|yandex(?:navi|maps):\\/\\/[\\w.,@?^=%&:\\/~+#|-]*[\\w@?^=%&\\/~+#|-]" +
+		// Geo URIs carry their coordinates and parameters in place of the host, followed by the `q=lat,lng(Label)` of Android,
+		// e.g. `geo:52.2,21.0;u=35` or `geo:0,0?q=52.2,21.0(Label)`.
+		"|\\b(?i:geo):-?\\d+(?:\\.\\d+)?,-?\\d+(?:\\.\\d+)?(?:,-?\\d+(?:\\.\\d+)?)?(?:;[\\w.%=-]*[\\w%-])*" +
+		"(?:\\?[\\w.,@?^=%&:\\/~+#()-]*[\\w@?^=%&\\/~+#)-])?"
 )
 
 // ParseFirstUrl attempts to parse the first URL found in the given text using a regular expression.
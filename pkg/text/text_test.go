@@ -36,3 +36,129 @@ func TestParseFirstUrl_ShortenedURL(t *testing.T) {
 		t.Errorf("Expected URL %q but got %q", expectedURL, actualURL)
 	}
 }
+
+func TestParseFirstUrl_Ge0URL(t *testing.T) {
+	text := "Meet me here ge0://w4NCMunrVR/Hala_Stulecia tomorrow"
+	expectedURL, _ := url.Parse("ge0://w4NCMunrVR/Hala_Stulecia")
+
+	actualURL, actualError := ParseFirstUrl(text)
+
+	if actualError != nil {
+		t.Errorf("Expected no error but got %v", actualError)
+	}
+
+	if actualURL.String() != expectedURL.String() {
+		t.Errorf("Expected URL %q but got %q", expectedURL, actualURL)
+	}
+}
+
+func TestParseFirstUrl_PipesAndBrackets(t *testing.T) {
+	text := "Route https://2gis.ru/moscow/directions/points/|37.617635,55.755814;4504235282610451 and more"
+	expectedURL := "https://2gis.ru/moscow/directions/points/|37.617635,55.755814;4504235282610451"
+
+	actualURL, actualError := ParseFirstUrl(text)
+
+	if actualError != nil {
+		t.Errorf("Expected no error but got %v", actualError)
+	}
+
+	if actualURL.Path != "/moscow/directions/points/|37.617635,55.755814;4504235282610451" {
+		t.Errorf("Expected URL %q but got %q", expectedURL, actualURL)
+	}
+
+	text = "https://yandex.ru/maps/?whatshere[point]=37.617635,55.755814&z=17"
+
+	actualURL, actualError = ParseFirstUrl(text)
+
+	if actualError != nil {
+		t.Errorf("Expected no error but got %v", actualError)
+	}
+
+	if actualURL.Query().Get("whatshere[point]") != "37.617635,55.755814" {
+		t.Errorf("Expected URL %q but got %q", text, actualURL)
+	}
+}
+
+func TestParseFirstUrl_YandexNavigatorURL(t *testing.T) {
+	text := "Go yandexnavi://build_route_on_map?lat_to=55.755814&lon_to=37.617635 now"
+	expectedURL, _ := url.Parse("yandexnavi://build_route_on_map?lat_to=55.755814&lon_to=37.617635")
+
+	actualURL, actualError := ParseFirstUrl(text)
+
+	if actualError != nil {
+		t.Errorf("Expected no error but got %v", actualError)
+	}
+
+	if actualURL.String() != expectedURL.String() {
+		t.Errorf("Expected URL %q but got %q", expectedURL, actualURL)
+	}
+}
+
+func TestParseFirstUrl_GeoURI(t *testing.T) {
+	text := "Meet me here geo:0,0?q=51.1069402,17.0772095(Hala+Stulecia) tomorrow"
+	expectedURL, _ := url.Parse("geo:0,0?q=51.1069402,17.0772095(Hala+Stulecia)")
+
+	actualURL, actualError := ParseFirstUrl(text)
+
+	if actualError != nil {
+		t.Errorf("Expected no error but got %v", actualError)
+	}
+
+	if actualURL.String() != expectedURL.String() {
+		t.Errorf("Expected URL %q but got %q", expectedURL, actualURL)
+	}
+
+	text = "Pin: geo:52.2297,21.0122,100;crs=wgs84;u=35."
+	expectedURL, _ = url.Parse("geo:52.2297,21.0122,100;crs=wgs84;u=35")
+
+	actualURL, actualError = ParseFirstUrl(text)
+
+	if actualError != nil {
+		t.Errorf("Expected no error but got %v", actualError)
+	}
+
+	if actualURL.String() != expectedURL.String() {
+		t.Errorf("Expected URL %q but got %q", expectedURL, actualURL)
+	}
+}
+
+func TestParseFirstUrl_GoogleMapsData(t *testing.T) {
+	text := "Here https://www.google.com/maps/place/Hala+Stulecia/@51.1069402,17.0772095,17z/data=!3m1!4b1!4m6!3m5!1s0x470fe9c2d4b58b3f:0x1!8m2!3d51.1069402!4d17.0772095!16s%2Fm%2F02r5mz*21 see you"
+	expectedPath := "/maps/place/Hala+Stulecia/@51.1069402,17.0772095,17z/data=!3m1!4b1!4m6!3m5!1s0x470fe9c2d4b58b3f:0x1!8m2!3d51.1069402!4d17.0772095!16s%2Fm%2F02r5mz*21"
+
+	actualURL, actualError := ParseFirstUrl(text)
+
+	if actualError != nil {
+		t.Errorf("Expected no error but got %v", actualError)
+	}
+
+	if actualURL.EscapedPath() != expectedPath {
+		t.Errorf("Expected path %q but got %q", expectedPath, actualURL.EscapedPath())
+	}
+}
+
+func TestParseFirstUrl_TrailingPunctuation(t *testing.T) {
+	testCases := []struct {
+		name        string
+		text        string
+		expectedURL string
+	}{
+		{name: "Exclamation mark", text: "Look here https://maps.app.goo.gl/AbCd123!", expectedURL: "https://maps.app.goo.gl/AbCd123"},
+		{name: "Markdown bold", text: "**https://maps.app.goo.gl/AbCd123**", expectedURL: "https://maps.app.goo.gl/AbCd123"},
+		{name: "Square brackets", text: "[https://maps.app.goo.gl/AbCd123]", expectedURL: "https://maps.app.goo.gl/AbCd123"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualURL, actualError := ParseFirstUrl(tc.text)
+
+			if actualError != nil {
+				t.Errorf("Expected no error but got %v", actualError)
+			}
+
+			if actualURL.String() != tc.expectedURL {
+				t.Errorf("Expected URL %q but got %q", tc.expectedURL, actualURL)
+			}
+		})
+	}
+}